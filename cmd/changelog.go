@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/RyanTalbot/bartle/internal/changelog"
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/RyanTalbot/bartle/internal/release"
+	"github.com/RyanTalbot/bartle/internal/semver"
+	"github.com/spf13/cobra"
+)
+
+var (
+	changelogFrom    string
+	changelogTo      string
+	changelogVersion string
+	changelogOutput  string
+)
+
+func ChangelogCommand() *cobra.Command {
+	changelogCmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Generate a Markdown changelog from conventional commits",
+		Long: `Generate a Markdown changelog section from the commits between two refs.
+
+Commits are grouped by type into the sections configured under
+changelog.sections (in that order); types not listed are left out. Breaking
+changes are always called out first, and scopes are shown as a bold prefix.
+
+The range ends at --to (default HEAD) and, without --from, starts at the
+latest version tag before it, so a tag already on --to doesn't empty the
+section. Without --version the section is titled with the version bartle
+next-version would pick, which always follows the latest tag whatever --from
+says; --version is then required when --to isn't HEAD.
+
+With -o/--output the section is prepended to that file (created if missing),
+keeping the title, any hand-written introduction and older releases intact.`,
+		Example: `
+  bartle changelog
+  bartle changelog -o CHANGELOG.md
+  bartle changelog --from v1.2.0 --to v1.3.0 --version v1.3.0`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadLintConfig()
+			if err != nil {
+				return err
+			}
+
+			from, to, version := changelogFrom, changelogTo, changelogVersion
+			if to == "" {
+				to = "HEAD"
+			}
+
+			if version == "" {
+				if to != "HEAD" {
+					return errors.New("--version is required when --to is not HEAD")
+				}
+				// The version always follows the latest tag; --from, which
+				// may be any ref, only moves the start of the range.
+				plan, err := planNextVersion(cfg.Release.TagPrefix, "", cfg.Release)
+				if err != nil {
+					return err
+				}
+				if plan.Bump == semver.BumpNone {
+					return errors.New("no releasable changes since the last version tag (pass --version to force a section)")
+				}
+				version = cfg.Release.TagPrefix + plan.Next.String()
+			}
+			if from == "" {
+				tag, found, err := release.PreviousTag(cfg.Release.TagPrefix, to)
+				if err != nil {
+					return fmt.Errorf("find previous tag: %w", err)
+				}
+				if found {
+					from = tag
+				}
+			}
+
+			commits, err := git.Commits(from, to)
+			if err != nil {
+				return fmt.Errorf("list commits: %w", err)
+			}
+
+			section := changelog.Render(changelog.Release{
+				Version: version,
+				Date:    time.Now(),
+				Changes: release.Classify(commits, cfg.Release),
+			}, cfg.Changelog)
+
+			if changelogOutput == "" {
+				_, err := fmt.Fprint(cmd.OutOrStdout(), section)
+				return err
+			}
+
+			existing, err := os.ReadFile(changelogOutput)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("read changelog: %w", err)
+			}
+			updated, err := changelog.Prepend(string(existing), section, version)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(changelogOutput, []byte(updated), 0o644); err != nil {
+				return fmt.Errorf("write changelog: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "✅ Added %s to %s\n", version, changelogOutput)
+			return nil
+		},
+	}
+
+	changelogCmd.Flags().StringVar(&changelogFrom, "from", "", "start after this ref (default: latest version tag before --to)")
+	changelogCmd.Flags().StringVar(&changelogTo, "to", "", "end at this ref (default HEAD)")
+	changelogCmd.Flags().StringVar(&changelogVersion, "version", "", "version heading for the section (default: next version)")
+	changelogCmd.Flags().StringVarP(&changelogOutput, "output", "o", "", "prepend the section to this file instead of printing it")
+
+	return changelogCmd
+}
+
+func init() {
+	rootCmd.AddCommand(ChangelogCommand())
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChangelogRange(t *testing.T) {
+	initRepo(t)
+	commit(t, "feat: shipped feature")
+	runGit(t, "tag", "v1.0.0")
+	commit(t, "fix: new fix")
+
+	tests := []struct {
+		name    string
+		args    []string
+		tag     string // tagged on HEAD first
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "next version",
+			want:    []string{"## v1.0.1", "new fix"},
+			notWant: []string{"shipped feature", "add bartle config"},
+		},
+		{
+			name:    "pinned version starts at the latest tag",
+			args:    []string{"--version", "v1.0.1"},
+			want:    []string{"## v1.0.1", "new fix"},
+			notWant: []string{"shipped feature"},
+		},
+		{
+			name:    "pinned version with its tag already on HEAD",
+			args:    []string{"--version", "v1.0.1"},
+			tag:     "v1.0.1",
+			want:    []string{"## v1.0.1", "new fix"},
+			notWant: []string{"shipped feature"},
+		},
+		{
+			name:    "explicit range",
+			args:    []string{"--from", "v1.0.0~1", "--to", "v1.0.0", "--version", "v1.0.0"},
+			want:    []string{"## v1.0.0", "shipped feature"},
+			notWant: []string{"new fix"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.tag != "" {
+				runGit(t, "tag", tt.tag)
+				defer runGit(t, "tag", "-d", tt.tag)
+			}
+			out, err := executeCommand(ChangelogCommand(), tt.args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, out)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("output lacks %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("output has %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
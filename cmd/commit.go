@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/RyanTalbot/bartle/internal/compose"
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/RyanTalbot/bartle/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commitAll    bool
+	commitDryRun bool
+)
+
+func CommitCommand() *cobra.Command {
+	commitCmd := &cobra.Command{
+		Use:   "commit",
+		Short: "Compose a commit message interactively and commit",
+		Long: `Walk through type, scope, subject, body, breaking change and footers,
+checking each answer against .bartle.yaml as you go, then run git commit -F
+with the result. The composed message always passes bartle lint.
+
+Only the conventional style is supported.`,
+		Example: `
+  bartle commit
+  bartle commit -a
+  bartle commit --dry-run  # print the message instead of committing`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadStagedConfig()
+			if err != nil {
+				return err
+			}
+			if style, _ := lint.LookupStyle(cfg.Style); style.Name() != "conventional" {
+				return fmt.Errorf("bartle commit only composes conventional commits (style is %q)", cfg.Style)
+			}
+
+			out := cmd.OutOrStdout()
+			p := compose.NewPrompter(cmd.InOrStdin(), out, cfg)
+
+			draft, err := p.Run()
+			if err != nil {
+				return err
+			}
+			msg := draft.Message()
+
+			if commitDryRun {
+				fmt.Fprintln(out, msg)
+				return nil
+			}
+
+			fmt.Fprintf(out, "\n%s\n\n", msg)
+			ok, err := p.Confirm("Commit with this message?", true)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return compose.ErrAborted
+			}
+
+			f, err := os.CreateTemp("", "bartle-commit-*.txt")
+			if err != nil {
+				return fmt.Errorf("create message file: %w", err)
+			}
+			defer os.Remove(f.Name())
+			if _, err := f.WriteString(msg + "\n"); err != nil {
+				f.Close()
+				return fmt.Errorf("write message file: %w", err)
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("write message file: %w", err)
+			}
+
+			gitArgs := []string{"commit", "-F", f.Name()}
+			if commitAll {
+				gitArgs = append(gitArgs, "--all")
+			}
+			res, err := git.Run(gitArgs...)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, res)
+			return nil
+		},
+	}
+
+	commitCmd.Flags().BoolVarP(&commitAll, "all", "a", false, "stage modified and deleted files before committing (git commit --all)")
+	commitCmd.Flags().BoolVar(&commitDryRun, "dry-run", false, "print the composed message instead of committing")
+
+	return commitCmd
+}
+
+func init() {
+	rootCmd.AddCommand(CommitCommand())
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+var migrateDryRun bool
+
+func ConfigCommand() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect, check and migrate .bartle.yaml",
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective config and where each value came from",
+		Long: `Print the config bartle uses in this repository: the built-in defaults
+merged with the global config, .bartle.yaml and anything they extend. Each
+value is followed by the file and line that set it, or "default".
+
+If the config has problems (a file that doesn't parse, unknown keys, bad
+values), they are printed instead, as with bartle config validate, and the
+exit code is non-zero.`,
+		Example: `
+  bartle config show`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := inspectConfig(cmd)
+			if err != nil {
+				return err
+			}
+			// bartle lint refuses a config with problems, so don't present
+			// what the remaining files add up to as the effective one.
+			if problems := configProblems(r); len(problems) > 0 {
+				printConfigProblems(cmd.ErrOrStderr(), problems)
+				return errLintFailed
+			}
+			out, err := config.Annotate(r.Config, r.Sources)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Report every problem in the config",
+		Long: `Load the config the way bartle lint does, but report every problem with
+the file and line it comes from instead of stopping at the first one. The
+exit code is non-zero when there are problems.`,
+		Example: `
+  bartle config validate`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := inspectConfig(cmd)
+			if err != nil {
+				return err
+			}
+			problems := configProblems(r)
+			if len(problems) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "✅ Config is valid!")
+				return nil
+			}
+			printConfigProblems(cmd.OutOrStdout(), problems)
+			return errLintFailed
+		},
+	}
+
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print a JSON Schema for .bartle.yaml",
+		Long: `Print a JSON Schema describing .bartle.yaml, for editor completion and
+validation. With the YAML language server, reference it from the top of the
+file:
+
+  # yaml-language-server: $schema=.bartle.schema.json`,
+		Example: `
+  bartle config schema > .bartle.schema.json`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var rules []string
+			for _, r := range lint.Rules() {
+				rules = append(rules, r.ID)
+			}
+			schema, err := config.Schema(lint.StyleNames(), rules)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(schema))
+			return nil
+		},
+	}
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate [file]",
+		Short: "Rewrite a config file to the current schema version",
+		Long: `Upgrade a config file written for an older bartle to the current schema
+version. Comments and key order are kept, but blank lines are dropped and
+comment spacing is normalised; use --dry-run to review the result first.
+The file defaults to the one given with --config, else the repository's
+config; files already at the current version are left untouched. Only YAML files can be migrated.`,
+		Example: `
+  bartle config migrate
+  bartle config migrate --dry-run ~/.config/bartle/config.yaml`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var path string
+			switch {
+			case len(args) == 1:
+				path = args[0]
+			case configFile != "":
+				path = configFile
+			default:
+				var err error
+				if path, err = config.RepoConfigPath(); err != nil {
+					return fmt.Errorf("%w (pass the file to migrate)", err)
+				}
+			}
+			if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+				return fmt.Errorf("only YAML configs can be migrated; set version to %d in %s by hand", config.CurrentVersion, path)
+			}
+
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("read config: %w", err)
+			}
+			migrated, from, err := config.Migrate(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+
+			out := cmd.OutOrStdout()
+			if migrateDryRun {
+				_, err := out.Write(migrated)
+				return err
+			}
+			if from == config.CurrentVersion {
+				fmt.Fprintf(out, "✅ %s is already at version %d\n", path, from)
+				return nil
+			}
+			st, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("stat config: %w", err)
+			}
+			if err := os.WriteFile(path, migrated, st.Mode().Perm()); err != nil {
+				return fmt.Errorf("write config: %w", err)
+			}
+			fmt.Fprintf(out, "✅ Migrated %s from version %d to %d\n", path, from, config.CurrentVersion)
+			return nil
+		},
+	}
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "print the migrated file instead of writing it")
+
+	configCmd.AddCommand(showCmd, validateCmd, schemaCmd, migrateCmd)
+	return configCmd
+}
+
+func init() {
+	rootCmd.AddCommand(ConfigCommand())
+}
+
+// inspectConfig loads the config for show and validate. Running without a
+// config file is fine; the defaults are reported.
+func inspectConfig(cmd *cobra.Command) (config.Report, error) {
+	r, err := config.Inspect(nil)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "ℹ️  no %s, using built-in defaults\n", filepath.Base(r.Path))
+		return r, nil
+	}
+	return r, err
+}
+
+// configProblems is everything that stops bartle lint from using the config:
+// problems loading it and values the styles and rules don't accept.
+func configProblems(r config.Report) []config.Problem {
+	problems := r.Problems
+	for _, p := range lint.ConfigProblems(r.Config) {
+		problems = append(problems, r.Sources.Locate(p))
+	}
+	return problems
+}
+
+func printConfigProblems(w io.Writer, problems []config.Problem) {
+	fmt.Fprintf(w, "❌ %d problem(s) in the config:\n", len(problems))
+	for _, p := range problems {
+		fmt.Fprintf(w, " - %v\n", p)
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigMigrateUsesConfigFlag(t *testing.T) {
+	dir := initRepo(t)
+	ci := filepath.Join(dir, "ci.yaml")
+	if err := os.WriteFile(ci, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		configFile string
+		args       []string
+		want       string
+	}{
+		{name: "repo config by default", want: filepath.Join(dir, ".bartle.yaml")},
+		{name: "--config", configFile: ci, want: ci},
+		{name: "argument wins over --config", configFile: ci, args: []string{".bartle.yaml"}, want: ".bartle.yaml is already"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := configFile
+			configFile = tt.configFile
+			t.Cleanup(func() { configFile = old })
+
+			out, err := executeCommand(ConfigCommand(), append([]string{"migrate"}, tt.args...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Fatalf("output lacks %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/RyanTalbot/bartle/internal/doctor"
+	"github.com/spf13/cobra"
+)
+
+func DoctorCommand() *cobra.Command {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that bartle is set up and its hooks will run",
+		Long: `Diagnose the usual reasons a hook doesn't run: not being in a repository,
+a missing or invalid .bartle.yaml, a hook that isn't installed, is outdated,
+isn't executable, points at a bartle binary that doesn't resolve, or is
+shadowed by core.hooksPath, and AI credentials that can't be found.
+
+Each check prints pass, warn or fail with a hint on how to fix it. The exit
+code is non-zero when any check fails.`,
+		Example: `
+  bartle doctor`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			checks := doctor.Run()
+
+			out := cmd.OutOrStdout()
+			for _, c := range checks {
+				mark := "✅"
+				switch c.Status {
+				case doctor.Warn:
+					mark = "⚠️ "
+				case doctor.Fail:
+					mark = "❌"
+				}
+				fmt.Fprintf(out, "%s %s: %s\n", mark, c.Name, c.Message)
+				if c.Hint != "" {
+					fmt.Fprintf(out, "   → %s\n", c.Hint)
+				}
+			}
+
+			if doctor.Failed(checks) {
+				return errLintFailed
+			}
+			return nil
+		},
+	}
+
+	return doctorCmd
+}
+
+func init() {
+	rootCmd.AddCommand(DoctorCommand())
+}
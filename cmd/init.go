@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/lint"
 	"github.com/RyanTalbot/bartle/internal/templates"
 	"github.com/spf13/cobra"
 )
@@ -29,10 +32,20 @@ Edit the file in your editor after generation.`,
   bartle init -s custom -f  # combine flags separately (-s jira -f)`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Ensure we’re inside a git repo
-			target := repoConfigPath()
-			if target == "" {
+			existing, err := config.RepoConfigPath()
+			if errors.Is(err, config.ErrNotInGitRepo) {
 				return fmt.Errorf("not inside a git repository (run `git init` first)")
 			}
+			if err != nil {
+				return err
+			}
+			target := filepath.Join(filepath.Dir(existing), config.FileName)
+
+			// A .bartle.json, .bartle.toml or package.json config can't be
+			// overwritten with YAML, and one next to it would be ambiguous.
+			if existing != target {
+				return fmt.Errorf("%s already configures bartle (remove it to generate %s)", existing, config.FileName)
+			}
 
 			// Ensure dir exists, really shouldn't ever hit this.
 			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
@@ -46,21 +59,22 @@ Edit the file in your editor after generation.`,
 
 			// TODO: We could add the creation of a backup file here if force is used.
 
-			style := strings.ToLower(initStyle)
-			validStyles := map[string]bool{"conventional": true, "jira": true, "custom": true}
-			if !validStyles[style] {
-				return fmt.Errorf("invalid --style %q (allowed: conventional|jira|custom)", initStyle)
+			style, ok := lint.LookupStyle(initStyle)
+			if !ok {
+				return fmt.Errorf("invalid --style %q (allowed: %s)", initStyle, strings.Join(lint.StyleNames(), "|"))
 			}
 
 			// Render the correct template with defaults
-			tmplStr := pickInitTemplate(style)
+			tmplStr := pickInitTemplate(style.Name())
 
 			tpl, err := template.New("cfg").Parse(tmplStr)
 			if err != nil {
 				return fmt.Errorf("parse template: %w", err)
 			}
 			var buf bytes.Buffer
-			if err := tpl.Execute(&buf, defaultTemplateData()); err != nil {
+			data := defaultTemplateData()
+			data.Style = style.Name()
+			if err := tpl.Execute(&buf, data); err != nil {
 				return fmt.Errorf("render template: %w", err)
 			}
 
@@ -82,7 +96,7 @@ Edit the file in your editor after generation.`,
 	// sensible defaults for flags
 	initStyle = "conventional"
 
-	initCmd.Flags().StringVarP(&initStyle, "style", "s", initStyle, "style: conventional|jira|custom")
+	initCmd.Flags().StringVarP(&initStyle, "style", "s", initStyle, "style: "+strings.Join(lint.StyleNames(), "|"))
 	initCmd.Flags().BoolVarP(&initForce, "force", "f", false, "overwrite existing config if it already exists")
 
 	return initCmd
@@ -92,44 +106,41 @@ func init() {
 	rootCmd.AddCommand(InitCommand())
 }
 
-func repoConfigPath() string {
-	dir, _ := os.Getwd()
-	for {
-		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
-			return filepath.Join(dir, ".bartle.yaml")
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			return ""
-		}
-		dir = parent
-	}
-}
-
 type templateData struct {
+	Version        int
+	Style          string
 	AIEnabled      bool
 	Model          string
 	APIKey         string
 	ScopeRequired  bool
 	MaxLen         int
+	BodyMaxLen     int
 	LowercaseStart bool
 	AutoApply      bool
 	BlockOnFail    bool
 }
 
+// defaultTemplateData fills the templates from config.Default, so a freshly
+// generated file states the defaults rather than a copy of them.
 func defaultTemplateData() templateData {
+	def := config.Default()
 	return templateData{
-		AIEnabled:      false,
-		Model:          "gpt-5",
-		APIKey:         "env:OPENAI_API_KEY",
-		ScopeRequired:  true,
-		MaxLen:         72,
-		LowercaseStart: false,
-		AutoApply:      false,
-		BlockOnFail:    true,
+		Version:        def.Version,
+		Style:          def.Style,
+		AIEnabled:      def.AI.Enabled,
+		Model:          def.AI.Model,
+		APIKey:         def.AI.APIKey,
+		ScopeRequired:  def.Rules.ScopeRequired,
+		MaxLen:         def.Rules.MaxLineLength,
+		BodyMaxLen:     def.Rules.BodyMaxLineLength,
+		LowercaseStart: def.Rules.LowercaseStart,
+		AutoApply:      def.Hook.AutoApply,
+		BlockOnFail:    def.Hook.BlockOnFail,
 	}
 }
 
+// pickInitTemplate returns the template for style. Styles without one of
+// their own get the conventional template, which takes the style name.
 func pickInitTemplate(style string) string {
 	switch strings.ToLower(style) {
 	case "jira":
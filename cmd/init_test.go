@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/lint"
+	"gopkg.in/yaml.v3"
+)
+
+// The conventional template spells out every default; keep the two in step.
+func TestConventionalTemplateMatchesDefault(t *testing.T) {
+	tpl := template.Must(template.New("cfg").Parse(pickInitTemplate("conventional")))
+	var rendered bytes.Buffer
+	if err := tpl.Execute(&rendered, defaultTemplateData()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got config.Config
+	dec := yaml.NewDecoder(&rendered)
+	dec.KnownFields(true)
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("template does not decode: %v", err)
+	}
+
+	// Compare as YAML so nil and empty lists are equal.
+	gotYAML, _ := yaml.Marshal(got)
+	wantYAML, _ := yaml.Marshal(config.Default())
+	if !bytes.Equal(gotYAML, wantYAML) {
+		t.Fatalf("template and config.Default differ\ntemplate:\n%s\ndefault:\n%s", gotYAML, wantYAML)
+	}
+}
+
+// teamStyle is conventional under another name, standing in for a style
+// registered after init's templates were written.
+type teamStyle struct{ lint.Style }
+
+func (teamStyle) Name() string { return "team" }
+
+func TestInitAcceptsRegisteredStyle(t *testing.T) {
+	if _, ok := lint.LookupStyle("team"); !ok {
+		conventional, _ := lint.LookupStyle("conventional")
+		lint.RegisterStyle(teamStyle{conventional})
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+
+	if _, err := executeCommand(InitCommand(), "--style", "team"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Style != "team" {
+		t.Fatalf("style = %q, want team", cfg.Style)
+	}
+
+	_, err = executeCommand(InitCommand(), "--force", "--style", "nope")
+	if err == nil || !strings.Contains(err.Error(), "team") {
+		t.Fatalf("error = %v, want the registered styles listed", err)
+	}
+}
+
+func TestInitRefusesOtherConfigFormats(t *testing.T) {
+	for name, content := range map[string]string{
+		".bartle.json": `{}`,
+		".bartle.toml": "",
+		"package.json": `{"bartle": {}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			t.Chdir(root)
+
+			_, err := executeCommand(InitCommand(), "--force")
+			if err == nil || !strings.Contains(err.Error(), name+" already configures bartle") {
+				t.Fatalf("error = %v, want %s reported", err, name)
+			}
+			if _, err := os.Stat(filepath.Join(root, config.FileName)); !os.IsNotExist(err) {
+				t.Fatalf("%s written next to %s", config.FileName, name)
+			}
+		})
+	}
+}
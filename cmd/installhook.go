@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/RyanTalbot/bartle/internal/hooks"
 	"github.com/spf13/cobra"
@@ -11,29 +12,54 @@ import (
 var (
 	hookForce       bool
 	hookUseAbsolute bool
+	hookType        string
+	hookChain       string
 )
 
 func InstallHookCommand() *cobra.Command {
 	var installHookCmd = &cobra.Command{
 		Use:   "install-hook",
-		Short: "Install a git commit-msg hook that runs bartle lint",
-		Long: `Installs .git/hooks/commit-msg to enforce your .bartle.yaml rules on every commit.
+		Short: "Install a git hook that runs bartle",
+		Long: `Installs a commit-msg hook to enforce your .bartle.yaml rules on every commit.
 
-By default the hook invokes "bartle lint \"$1\"" (using PATH). Use --absolute to
+Hooks go where git looks for them: core.hooksPath when it is set, otherwise
+the hooks directory of the repository, which linked worktrees share with the
+main checkout and which submodules keep under the superproject's .git.
+
+With --type prepare-commit-msg, installs a hook that pre-fills new commit
+messages from the branch name instead (see branch.extract in .bartle.yaml).
+With --type pre-push, installs a hook that lints every outgoing commit and
+rejects pushing branches whose names break branch.prefixes / branch.patterns.
+It catches commits that skipped the commit-msg hook (--no-verify, rebases,
+other clients).
+
+If a hook bartle didn't write is already installed (a Gerrit Change-Id hook,
+a secret scanner), --chain keeps it: it is moved to <hook>.bartle-chained and
+run before bartle (--chain or --chain=before) or after it (--chain=after).
+bartle uninstall-hook puts it back exactly as it was.
+
+By default the hook invokes "bartle" from PATH. Use --absolute to
 embed the absolute path to the bartle binary in the hook for reliability.`,
 		Example: `
   bartle install-hook
   bartle install-hook --absolute
-  bartle install-hook --force`,
+  bartle install-hook --force
+  bartle install-hook --chain=after
+  bartle install-hook --type prepare-commit-msg
+  bartle install-hook --type pre-push`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			repoRoot, err := hooks.RepoRootFromCwd()
+			if !hooks.Supported(hookType) {
+				return fmt.Errorf("invalid --type %q (allowed: %s)", hookType, strings.Join(hooks.Names(), "|"))
+			}
+
+			repo, err := hooks.Locate()
 			if err != nil {
 				return err
 			}
 
-			hookPath := hooks.HookPath(repoRoot)
+			hookPath := repo.HookPath(hookType)
 
 			var bartleCmd string
 			if hookUseAbsolute {
@@ -46,27 +72,48 @@ embed the absolute path to the bartle binary in the hook for reliability.`,
 				bartleCmd = "bartle"
 			}
 
+			chain, err := hooks.ParseChain(hookChain)
+			if err != nil {
+				return fmt.Errorf("invalid --chain: %w", err)
+			}
+
 			// If a hook exists, decide whether we can/should overwrite
 			exists, isOurs, err := hooks.CheckExisting(hookPath)
 			if err != nil {
 				return err
 			}
-			if exists && !hookForce && !isOurs {
-				return fmt.Errorf("%s already exists and is not managed by Bartle (use --force to overwrite)", hookPath)
+			if exists && !hookForce && !isOurs && chain == hooks.ChainNone {
+				return fmt.Errorf("%s already exists and is not managed by Bartle (use --chain to keep it, or --force to overwrite)", hookPath)
 			}
 
-			if err := hooks.InstallCommitMsgHook(hookPath, bartleCmd); err != nil {
+			if err := hooks.InstallHook(hookPath, hookType, bartleCmd, chain); err != nil {
 				return err
 			}
 
-			fmt.Fprintln(cmd.OutOrStdout(), "✅ Installed Bartle commit-msg hook at", hookPath)
-			fmt.Fprintln(cmd.OutOrStdout(), "Commits will now be linted automatically.")
+			fmt.Fprintf(cmd.OutOrStdout(), "✅ Installed Bartle %s hook at %s\n", hookType, hookPath)
+			if repo.HooksPathSet {
+				fmt.Fprintln(cmd.OutOrStdout(), "ℹ️  core.hooksPath is set, so the hook went there instead of .git/hooks.")
+			}
+			if exists && !isOurs && chain != hooks.ChainNone {
+				fmt.Fprintf(cmd.OutOrStdout(), "The existing hook was kept at %s and runs %s bartle.\n", hooks.ChainedPath(hookPath), chain)
+			}
+			switch hookType {
+			case hooks.CommitMsg:
+				fmt.Fprintln(cmd.OutOrStdout(), "Commits will now be linted automatically.")
+			case hooks.PrepareCommitMsg:
+				fmt.Fprintln(cmd.OutOrStdout(), "New commit messages will be pre-filled from the branch name.")
+			case hooks.PrePush:
+				fmt.Fprintln(cmd.OutOrStdout(), "Outgoing commits and branch names will now be checked on push.")
+			}
 			return nil
 		},
 	}
 
 	installHookCmd.Flags().BoolVarP(&hookForce, "force", "f", false, "overwrite an existing hook")
 	installHookCmd.Flags().BoolVarP(&hookUseAbsolute, "absolute", "a", false, "embed absolute path to bartle binary in hook")
+	installHookCmd.Flags().StringVarP(&hookType, "type", "t", hooks.CommitMsg, "hook to install: "+strings.Join(hooks.Names(), "|"))
+	installHookCmd.Flags().StringVar(&hookChain, "chain", "", "keep an existing hook and run it before|after bartle")
+	installHookCmd.Flags().Lookup("chain").NoOptDefVal = string(hooks.ChainBefore)
 
 	return installHookCmd
 }
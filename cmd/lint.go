@@ -9,12 +9,20 @@ import (
 	"strings"
 
 	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/git"
 	"github.com/RyanTalbot/bartle/internal/lint"
+	"github.com/RyanTalbot/bartle/internal/report"
 	"github.com/spf13/cobra"
 )
 
 var (
-	lintMsg string
+	lintMsg    string
+	lintHook   bool
+	lintFix    bool
+	lintFrom   string
+	lintTo     string
+	lintFormat string
+	lintCI     string
 )
 
 func LintCommand() *cobra.Command {
@@ -24,15 +32,68 @@ func LintCommand() *cobra.Command {
 		Long: `Validate a commit message against the style and rules defined in .bartle.yaml.
 
 You can pass a message directly with -m/--message, a path to a message file
-(e.g. .git/COMMIT_EDITMSG), or pipe a message on stdin.`,
+(e.g. .git/COMMIT_EDITMSG), or pipe a message on stdin.
+
+With --hook (used by the installed commit-msg hook) the hook section of
+.bartle.yaml applies: hook.auto_apply rewrites the message file with automatic
+fixes before validation, and hook.block_on_fail: false reports problems
+without blocking the commit.
+
+With --fix, mechanical problems (type case, subject case when
+rules.lowercase_start is set, spacing after ':', a trailing period, CRLF line
+endings, a missing blank line after the header) are repaired first. A message
+file is rewritten in place; a message from -m or stdin is printed to stdout
+with the report going to stderr.
+
+With --from (and optionally --to, default HEAD) every non-merge commit in
+from..to is linted instead, e.g. all commits of a pull request in CI.
+
+--format selects the output: text (default), json, sarif, junit or
+checkstyle for CI dashboards and code-scanning uploads, github for workflow
+commands or gitlab for a code quality report. The exit code is the same
+whatever the format.
+
+--ci (default auto) adds native CI annotations: under GitHub Actions
+(GITHUB_ACTIONS=true) ::error/::warning workflow commands are printed so
+failures show inline on the pull request; under GitLab CI (GITLAB_CI=true) a
+code quality report is written to gl-code-quality-report.json for
+artifacts:reports:codequality, whatever the format. Workflow commands need
+text output, so auto skips them with another --format and --ci github is an
+error. With --hook, auto means off.`,
 		Example: `
   bartle lint -m "feat(ui): add dropdown"
+  bartle lint --fix .git/COMMIT_EDITMSG
+  bartle lint --fix -m "Feat(ui): Add dropdown."
   bartle lint .git/COMMIT_EDITMSG
-  echo "fix(api): handle nil pointer" | bartle lint`,
-		Args:          cobra.MaximumNArgs(1),
-		SilenceUsage:  true, // don't print usage on lint failures
-		SilenceErrors: true, // we've already printed friendly errors
+  echo "fix(api): handle nil pointer" | bartle lint
+  bartle lint --from origin/main --to HEAD
+  bartle lint --from origin/main --format sarif > bartle.sarif`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true, // don't print usage on lint failures
 		RunE: func(cmd *cobra.Command, args []string) error {
+			structured := !strings.EqualFold(lintFormat, "text")
+			if structured && !report.Supported(lintFormat) {
+				return fmt.Errorf("invalid --format %q (allowed: text|%s)", lintFormat, strings.Join(report.Formats(), "|"))
+			}
+			ci, err := ciProvider(lintCI, structured, lintHook)
+			if err != nil {
+				return err
+			}
+
+			if lintFrom != "" || lintTo != "" {
+				if lintFrom == "" {
+					return errors.New("--to requires --from")
+				}
+				if len(args) > 0 || lintMsg != "" || lintFix || lintHook {
+					return errors.New("--from/--to can't be combined with a message, --fix or --hook")
+				}
+				// Fail on a broken config even when the range is empty.
+				if _, err := loadLintConfig(); err != nil {
+					return err
+				}
+				return lintRange(cmd, lintFrom, lintTo, lintFormat, ci)
+			}
+
 			msg := strings.TrimSpace(lintMsg)
 
 			if msg == "" && len(args) == 1 {
@@ -59,32 +120,87 @@ You can pass a message directly with -m/--message, a path to a message file
 				return errors.New("no commit message provided (use -m, a file path, or pipe on stdin)")
 			}
 
+			cfg, err := loadStagedConfig()
+			if err != nil {
+				return err
+			}
+
+			fromFile := len(args) == 1 && strings.TrimSpace(lintMsg) == ""
+			if structured && lintFix && !fromFile {
+				return errors.New("--fix with -m or stdin prints the fixed message and only supports --format text")
+			}
+			out := cmd.OutOrStdout()
+
+			if lintFix || (lintHook && cfg.Hook.AutoApply) {
+				fixed := lint.FixMessage(msg, cfg)
+				msg = fixed.Message
+
+				switch {
+				case fromFile && fixed.Changed():
+					if err := rewriteMessageFile(args[0], fixed.Message); err != nil {
+						return err
+					}
+				case !fromFile && lintFix:
+					// The fixed message is the output; keep the report off stdout.
+					fmt.Fprintln(cmd.OutOrStdout(), fixed.Message)
+					out = cmd.ErrOrStderr()
+				}
+				if fixed.Changed() && !structured {
+					printFixes(out, fixed)
+				}
+			}
+
 			// Normalize CRLF
 			msg = strings.ReplaceAll(msg, "\r", "")
 
-			// Load config from repo root
-			cfg, _, err := config.Load()
-			if err != nil {
-				return fmt.Errorf("load config: %w", err)
+			res := lint.ValidateMessage(msg, cfg)
+
+			entry := report.Entry{Result: res}
+			if fromFile {
+				entry.Source = args[0]
+			}
+
+			notices := out
+			if structured {
+				if err := report.Write(out, lintFormat, []report.Entry{entry}); err != nil {
+					return fmt.Errorf("write report: %w", err)
+				}
+				notices = cmd.ErrOrStderr()
+			} else {
+				if res.Valid {
+					fmt.Fprintln(out, "✅ Commit message is valid!")
+					printWarnings(out, res)
+				} else {
+					fmt.Fprintln(out, "❌ Invalid commit message:")
+					printErrors(out, res)
+					printWarnings(out, res)
+				}
+			}
+			if err := emitCIAnnotations(notices, ci, []report.Entry{entry}); err != nil {
+				return err
 			}
 
-			res := lint.ValidateMessage(msg, cfg)
 			if res.Valid {
-				fmt.Fprintln(cmd.OutOrStdout(), "✅ Commit message is valid!")
 				return nil
 			}
 
-			fmt.Fprintln(cmd.OutOrStdout(), "❌ Invalid commit message:")
-			for _, e := range res.Errors {
-				fmt.Fprintln(cmd.OutOrStdout(), e)
+			if lintHook && !cfg.Hook.BlockOnFail {
+				fmt.Fprintln(cmd.ErrOrStderr(), "ℹ️  hook.block_on_fail is false, so the commit is allowed anyway.")
+				return nil
 			}
 
 			// Return an error to produce non-zero exit code (hooks/CI),
 			// but we've already printed the friendly output above.
-			return errors.New("lint failed")
+			return errLintFailed
 		},
 	}
 	lintCmd.Flags().StringVarP(&lintMsg, "message", "m", "", "commit message text to lint")
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "apply automatic fixes before linting")
+	lintCmd.Flags().StringVar(&lintFrom, "from", "", "lint every commit after this revision (exclusive)")
+	lintCmd.Flags().StringVar(&lintTo, "to", "", "last revision to lint with --from (default HEAD)")
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", "output format: text|"+strings.Join(report.Formats(), "|"))
+	lintCmd.Flags().StringVar(&lintCI, "ci", "auto", "CI annotations: auto|github|gitlab|off (auto is off with --hook)")
+	lintCmd.Flags().BoolVar(&lintHook, "hook", false, "run as the commit-msg hook (honors hook.auto_apply and hook.block_on_fail)")
 
 	return lintCmd
 }
@@ -93,6 +209,164 @@ func init() {
 	rootCmd.AddCommand(LintCommand())
 }
 
+// loadLintConfig loads .bartle.yaml from the repo root and checks it against
+// the registered styles and rules.
+func loadLintConfig() (config.Config, error) {
+	return loadLintConfigFor(nil)
+}
+
+// loadLintConfigFor also applies the nested .bartle.yaml files for paths.
+func loadLintConfigFor(paths []string) (config.Config, error) {
+	cfg, _, err := config.LoadFor(paths)
+	if err != nil {
+		return cfg, fmt.Errorf("load config: %w", err)
+	}
+	if err := lint.CheckConfig(cfg); err != nil {
+		return cfg, fmt.Errorf("load config: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadStagedConfig loads the config for the commit being made: nested
+// configs apply when the staged files are below them.
+func loadStagedConfig() (config.Config, error) {
+	// Without a readable index (e.g. outside a repo) the loader reports
+	// the real problem.
+	files, _ := git.StagedFiles()
+	return loadLintConfigFor(files)
+}
+
+// commitConfig loads the config for a commit from the files it touches, so
+// nested configs apply to range and pre-push linting too.
+func commitConfig(sha string) (config.Config, error) {
+	files, err := git.ChangedFiles(sha)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("list files changed by %s: %w", shortSHA(sha), err)
+	}
+	return loadLintConfigFor(files)
+}
+
+// ciProvider resolves --ci to the provider to annotate for, "" for none.
+// auto detects it from the environment, except in hook mode, where a hook
+// running in CI (a release or bot commit) shouldn't annotate the job or leave
+// a report in the working tree. Workflow commands go to stdout, so they can't
+// be combined with a structured --format; the GitLab report is a file and
+// works with any.
+func ciProvider(mode string, structured, hook bool) (string, error) {
+	provider := strings.ToLower(mode)
+	switch provider {
+	case "off", "":
+		return "", nil
+	case "auto":
+		if hook {
+			return "", nil
+		}
+		provider = report.DetectCI(os.Getenv)
+		if structured && provider == report.CIGitHub {
+			return "", nil
+		}
+		return provider, nil
+	case report.CIGitHub:
+		if structured {
+			return "", errors.New("--ci github prints workflow commands to stdout and needs --format text")
+		}
+		return provider, nil
+	case report.CIGitLab:
+		return provider, nil
+	default:
+		return "", fmt.Errorf("invalid --ci %q (allowed: auto|github|gitlab|off)", mode)
+	}
+}
+
+// emitCIAnnotations adds native CI output for provider (see ciProvider)
+// after the report.
+func emitCIAnnotations(out io.Writer, provider string, entries []report.Entry) error {
+	switch provider {
+	case report.CIGitHub:
+		return report.Write(out, "github", entries)
+	case report.CIGitLab:
+		f, err := os.Create(report.GitLabReportFile)
+		if err != nil {
+			return fmt.Errorf("create code quality report: %w", err)
+		}
+		defer f.Close()
+		if err := report.Write(f, "gitlab", entries); err != nil {
+			return fmt.Errorf("write code quality report: %w", err)
+		}
+		fmt.Fprintln(out, "📝 Wrote GitLab code quality report to", report.GitLabReportFile)
+	}
+	return nil
+}
+
+// printErrors lists blocking diagnostics.
+func printErrors(w io.Writer, res lint.Result) {
+	for _, d := range res.Errors() {
+		fmt.Fprintln(w, d)
+	}
+}
+
+// printWarnings lists non-blocking diagnostics, if any.
+func printWarnings(w io.Writer, res lint.Result) {
+	warnings := res.Warnings()
+	if len(warnings) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "⚠️  Warnings:")
+	for _, d := range warnings {
+		fmt.Fprintln(w, d)
+	}
+}
+
+// printFixes lists the automatic repairs applied to a message.
+func printFixes(w io.Writer, res lint.FixResult) {
+	fmt.Fprintln(w, "🔧 Applied fixes:")
+	for _, f := range res.Applied {
+		fmt.Fprintln(w, " -", f.Description)
+	}
+}
+
+// scissorsLine marks where `git commit -v` appends the diff; git drops it and
+// everything below it from the message.
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// rewriteMessageFile replaces the message in a COMMIT_EDITMSG-style file,
+// keeping git's comment lines and anything below a scissors line intact.
+func rewriteMessageFile(path, msg string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat message file: %w", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read message file: %w", err)
+	}
+
+	var comments []string
+	lines := strings.Split(strings.ReplaceAll(string(b), "\r", ""), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, scissorsLine) {
+			comments = append(comments, lines[i:]...)
+			break
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			comments = append(comments, line)
+		}
+	}
+
+	out := msg + "\n"
+	if len(comments) > 0 {
+		out += "\n" + strings.Join(comments, "\n")
+		if !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(out), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("write message file: %w", err)
+	}
+	return nil
+}
+
 // readStdinIfPiped returns stdin content if data is piped; otherwise "".
 func readStdinIfPiped() (string, error) {
 	info, err := os.Stdin.Stat()
@@ -124,10 +398,14 @@ func readStdinIfPiped() (string, error) {
 	return s, nil
 }
 
-// stripGitComments removes lines beginning with '#' which Git places in COMMIT_EDITMSG.
+// stripGitComments removes lines beginning with '#' which Git places in COMMIT_EDITMSG,
+// and everything from the scissors line down.
 func stripGitComments(s string) string {
 	var out []string
 	for _, line := range strings.Split(s, "\n") {
+		if strings.HasPrefix(line, scissorsLine) {
+			break
+		}
 		trim := strings.TrimSpace(line)
 		if strings.HasPrefix(trim, "#") {
 			continue
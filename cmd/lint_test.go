@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// verboseMessage is a COMMIT_EDITMSG as written by `git commit -v`.
+const verboseMessage = `FEAT: Add x.
+
+# Please enter the commit message for your changes.
+` + scissorsLine + `
+# Do not modify or remove the line above.
+diff --git a/x.go b/x.go
++package x
+`
+
+func TestStripGitCommentsStopsAtScissors(t *testing.T) {
+	if got := stripGitComments(verboseMessage); got != "FEAT: Add x." {
+		t.Fatalf("stripGitComments = %q", got)
+	}
+}
+
+func TestRewriteMessageFileKeepsDiffBelowScissors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte(verboseMessage), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := rewriteMessageFile(path, "feat: add x"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "feat: add x\n\n# Please enter the commit message for your changes.\n" + scissorsLine +
+		"\n# Do not modify or remove the line above.\ndiff --git a/x.go b/x.go\n+package x\n"
+	if string(b) != want {
+		t.Fatalf("file =\n%s\nwant\n%s", b, want)
+	}
+	if got := stripGitComments(string(b)); got != "feat: add x" {
+		t.Fatalf("message after rewrite = %q", got)
+	}
+}
+
+func TestCIProvider(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        string // CI detected from the environment
+		mode       string
+		structured bool
+		hook       bool
+		want       string
+		wantErr    string
+	}{
+		{name: "auto detects github", env: "GITHUB_ACTIONS", mode: "auto", want: "github"},
+		{name: "auto detects gitlab", env: "GITLAB_CI", mode: "auto", want: "gitlab"},
+		{name: "auto outside CI", mode: "auto", want: ""},
+		{name: "auto in a hook", env: "GITLAB_CI", mode: "auto", hook: true, want: ""},
+		{name: "explicit in a hook", mode: "gitlab", hook: true, want: "gitlab"},
+		{name: "auto gitlab with a structured format", env: "GITLAB_CI", mode: "auto", structured: true, want: "gitlab"},
+		{name: "auto github with a structured format", env: "GITHUB_ACTIONS", mode: "auto", structured: true, want: ""},
+		{name: "github with a structured format", mode: "github", structured: true, wantErr: "needs --format text"},
+		{name: "off", env: "GITHUB_ACTIONS", mode: "off", want: ""},
+		{name: "invalid", mode: "jenkins", structured: true, wantErr: `invalid --ci "jenkins"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_ACTIONS", "")
+			t.Setenv("GITLAB_CI", "")
+			if tt.env != "" {
+				t.Setenv(tt.env, "true")
+			}
+			got, err := ciProvider(tt.mode, tt.structured, tt.hook)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("ciProvider = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/RyanTalbot/bartle/internal/branch"
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/spf13/cobra"
+)
+
+func LintBranchCommand() *cobra.Command {
+	lintBranchCmd := &cobra.Command{
+		Use:   "lint-branch [name]",
+		Short: "Lint a branch name against .bartle.yaml rules",
+		Long: `Check a branch name (the current branch by default) against the branch
+section of .bartle.yaml: it must start with one of branch.prefixes or match
+one of branch.patterns. Names listed in branch.ignore are always accepted,
+and without prefixes or patterns every name is.
+
+To reject non-conforming branches on push, install the pre-push hook with
+bartle install-hook --type pre-push.`,
+		Example: `
+  bartle lint-branch
+  bartle lint-branch feat/ui-dropdown`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadLintConfig()
+			if err != nil {
+				return err
+			}
+
+			var name string
+			if len(args) == 1 {
+				name = args[0]
+			} else {
+				name, err = git.CurrentBranch()
+				if errors.Is(err, git.ErrDetachedHead) {
+					return errors.New("HEAD is detached; pass the branch name to lint")
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			problem, err := branch.Lint(name, cfg.Branch)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if problem == "" {
+				fmt.Fprintf(out, "✅ Branch name %q is valid!\n", name)
+				return nil
+			}
+			fmt.Fprintln(out, "❌ Invalid branch name:")
+			fmt.Fprintln(out, " -", problem)
+			return errLintFailed
+		},
+	}
+
+	return lintBranchCmd
+}
+
+func init() {
+	rootCmd.AddCommand(LintBranchCommand())
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/RyanTalbot/bartle/internal/lint"
+	"github.com/RyanTalbot/bartle/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// lintRange lints every commit in from..to and fails if any of them fails.
+// ci is the resolved provider to annotate for (see ciProvider).
+func lintRange(cmd *cobra.Command, from, to, format, ci string) error {
+	if to == "" {
+		to = "HEAD"
+	}
+
+	commits, err := git.Commits(from, to)
+	if err != nil {
+		return fmt.Errorf("list commits: %w", err)
+	}
+
+	entries, failed, err := lintCommits(commits)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	notices := out
+	if !strings.EqualFold(format, "text") {
+		if err := report.Write(out, format, entries); err != nil {
+			return fmt.Errorf("write report: %w", err)
+		}
+		notices = cmd.ErrOrStderr()
+	} else {
+		printRangeText(out, entries, from, to)
+	}
+	if err := emitCIAnnotations(notices, ci, entries); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return errLintFailed
+	}
+	return nil
+}
+
+// lintCommits validates each commit with the config for the files it
+// touches and counts the failures.
+func lintCommits(commits []git.Commit) ([]report.Entry, int, error) {
+	entries := make([]report.Entry, 0, len(commits))
+	failed := 0
+	for _, c := range commits {
+		cfg, err := commitConfig(c.SHA)
+		if err != nil {
+			return nil, 0, err
+		}
+		res := lint.ValidateMessage(c.Message, cfg)
+		if !res.Valid {
+			failed++
+		}
+		entries = append(entries, report.Entry{SHA: c.SHA, Subject: c.Subject(), Result: res})
+	}
+	return entries, failed, nil
+}
+
+func printRangeText(out io.Writer, entries []report.Entry, from, to string) {
+	if len(entries) == 0 {
+		fmt.Fprintf(out, "ℹ️  No commits in %s..%s to lint.\n", from, to)
+		return
+	}
+	printCommitEntries(out, entries)
+}
+
+// printCommitEntries prints one line per commit with its findings, then a
+// summary.
+func printCommitEntries(out io.Writer, entries []report.Entry) {
+	failed := 0
+	for _, e := range entries {
+		mark := "✅"
+		if !e.Result.Valid {
+			mark = "❌"
+			failed++
+		}
+		fmt.Fprintf(out, "%s %s %s\n", mark, shortSHA(e.SHA), e.Subject)
+		printErrors(out, e.Result)
+		printWarnings(out, e.Result)
+	}
+
+	fmt.Fprintf(out, "\n%d commit(s) checked, %d failed.\n", len(entries), failed)
+}
+
+func shortSHA(sha string) string {
+	return git.Commit{SHA: sha}.ShortSHA()
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/RyanTalbot/bartle/internal/report"
+)
+
+// initRepo creates a git repository in a temporary directory, changes into
+// it and commits a .bartle.yaml that doesn't require scopes.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, kv := range [][2]string{{"NAME", "Test"}, {"EMAIL", "test@example.com"}} {
+		t.Setenv("GIT_AUTHOR_"+kv[0], kv[1])
+		t.Setenv("GIT_COMMITTER_"+kv[0], kv[1])
+	}
+
+	runGit(t, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, ".bartle.yaml"), []byte("rules:\n  scope_required: false\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, "add", ".bartle.yaml")
+	commit(t, "chore: add bartle config")
+	return dir
+}
+
+func runGit(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := git.Run(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// commit makes an empty commit and returns its hash.
+func commit(t *testing.T, msg string) string {
+	t.Helper()
+	runGit(t, "commit", "-q", "--allow-empty", "--no-verify", "-m", msg)
+	return runGit(t, "rev-parse", "HEAD")
+}
+
+// runLint runs bartle lint, leaving error reporting to the test as the root
+// command does.
+func runLint(args ...string) (string, error) {
+	cmd := LintCommand()
+	cmd.SilenceErrors = true
+	return executeCommand(cmd, args...)
+}
+
+func TestLintRange(t *testing.T) {
+	initRepo(t)
+	base := runGit(t, "rev-parse", "HEAD")
+	feat := commit(t, "feat: add search")
+	runGit(t, "checkout", "-q", "-b", "side")
+	commit(t, "wip")
+	runGit(t, "checkout", "-q", "main")
+	commit(t, "fix: handle empty query")
+	runGit(t, "merge", "-q", "--no-ff", "--no-verify", "-m", "Merge branch 'side'", "side")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+		failed  bool   // exit code is a lint failure
+		wantErr string // any other error
+	}{
+		{
+			name:    "whole range skips the merge",
+			args:    []string{"--from", base},
+			want:    []string{"feat: add search", "❌ ", "wip", "fix: handle empty query", "3 commit(s) checked, 1 failed."},
+			notWant: []string{"chore: add bartle config", "Merge branch"},
+			failed:  true,
+		},
+		{
+			name:    "--to bounds the range",
+			args:    []string{"--from", base, "--to", feat},
+			want:    []string{"feat: add search", "1 commit(s) checked, 0 failed."},
+			notWant: []string{"wip", "fix: handle empty query"},
+		},
+		{
+			name: "empty range",
+			args: []string{"--from", "HEAD"},
+			want: []string{"No commits in HEAD..HEAD to lint."},
+		},
+		{
+			name:    "unknown revision",
+			args:    []string{"--from", "nope"},
+			wantErr: "list commits",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runLint(append(tt.args, "--ci", "off")...)
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+			case tt.failed != errors.Is(err, errLintFailed):
+				t.Fatalf("error = %v, want lint failure %v\n%s", err, tt.failed, out)
+			case err != nil && !tt.failed:
+				t.Fatalf("unexpected error: %v\n%s", err, out)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("output lacks %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("output has %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		out, err := runLint("--from", base, "--format", "json")
+		if !errors.Is(err, errLintFailed) {
+			t.Fatalf("error = %v, want a lint failure", err)
+		}
+		var got struct {
+			Valid   bool `json:"valid"`
+			Results []struct {
+				SHA   string `json:"sha"`
+				Valid bool   `json:"valid"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		if got.Valid || len(got.Results) != 3 || got.Results[0].SHA != feat || !got.Results[0].Valid || got.Results[1].Valid {
+			t.Fatalf("unexpected report: %+v", got)
+		}
+	})
+
+	t.Run("gitlab report with json", func(t *testing.T) {
+		t.Setenv("GITHUB_ACTIONS", "")
+		t.Setenv("GITLAB_CI", "true")
+		out, err := runLint("--from", base, "--format", "json")
+		if !errors.Is(err, errLintFailed) {
+			t.Fatalf("error = %v, want a lint failure", err)
+		}
+		if !strings.Contains(out, "Wrote GitLab code quality report") {
+			t.Fatalf("no notice:\n%s", out)
+		}
+		if _, err := os.Stat(report.GitLabReportFile); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
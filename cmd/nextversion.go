@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/RyanTalbot/bartle/internal/release"
+	"github.com/RyanTalbot/bartle/internal/semver"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nextVersionJSON  bool
+	nextVersionShort bool
+	nextVersionTag   string
+)
+
+func NextVersionCommand() *cobra.Command {
+	nextVersionCmd := &cobra.Command{
+		Use:   "next-version",
+		Short: "Compute the next semantic version from commit history",
+		Long: `Compute the next semantic version from the commits since the latest version tag.
+
+Commits are parsed with the same parser bartle lint uses. A breaking change
+(a '!' marker or a BREAKING CHANGE footer) bumps major; otherwise the commit
+type is looked up in release.bumps (by default feat → minor, fix → patch).
+Tags are matched with release.tag_prefix (default "v"). Without any version
+tag the whole history counts, starting from 0.0.0.
+
+Use -s for just the version number, -j for JSON.`,
+		Example: `
+  bartle next-version
+  bartle next-version -s
+  bartle next-version -j
+  bartle next-version --tag v1.4.0`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadLintConfig()
+			if err != nil {
+				return err
+			}
+			prefix := cfg.Release.TagPrefix
+
+			plan, err := planNextVersion(prefix, nextVersionTag, cfg.Release)
+			if err != nil {
+				return err
+			}
+
+			next := prefix + plan.Next.String()
+			out := cmd.OutOrStdout()
+
+			switch {
+			case nextVersionJSON:
+				payload := struct {
+					Current string `json:"current"`
+					Next    string `json:"next"`
+					Bump    string `json:"bump"`
+					Commits int    `json:"commits"`
+				}{Current: plan.CurrentTag, Next: next, Bump: string(plan.Bump), Commits: len(plan.Changes)}
+
+				encoder := json.NewEncoder(out)
+				encoder.SetEscapeHTML(false)
+				return encoder.Encode(payload)
+
+			case nextVersionShort:
+				_, err := fmt.Fprintln(out, next)
+				return err
+
+			default:
+				current := plan.CurrentTag
+				if current == "" {
+					current = "(no version tag)"
+				}
+				fmt.Fprintf(out, "%s → %s (%s bump, %d commit(s))\n", current, next, plan.Bump, len(plan.Changes))
+				for _, ch := range plan.Changes {
+					if ch.Bump == semver.BumpNone {
+						continue
+					}
+					fmt.Fprintf(out, "  %-5s %s %s\n", ch.Bump, ch.Commit.ShortSHA(), ch.Commit.Subject())
+				}
+				return nil
+			}
+		},
+	}
+
+	nextVersionCmd.Flags().BoolVarP(&nextVersionJSON, "json", "j", false, "print the result as JSON")
+	nextVersionCmd.Flags().BoolVarP(&nextVersionShort, "short", "s", false, "print only the next version")
+	nextVersionCmd.Flags().StringVar(&nextVersionTag, "tag", "", "start from this tag instead of the latest version tag")
+
+	return nextVersionCmd
+}
+
+func init() {
+	rootCmd.AddCommand(NextVersionCommand())
+}
+
+// planNextVersion finds the base tag (tag, or the latest version tag) and
+// plans the next release from the commits since then.
+func planNextVersion(prefix, tag string, rel config.Release) (release.Plan, error) {
+	var (
+		current semver.Version
+		err     error
+	)
+	if tag != "" {
+		current, err = semver.Parse(strings.TrimPrefix(tag, prefix))
+		if err != nil {
+			return release.Plan{}, fmt.Errorf("--tag: %w", err)
+		}
+	} else {
+		var found bool
+		tag, current, found, err = release.LatestTag(prefix)
+		if err != nil {
+			return release.Plan{}, fmt.Errorf("find latest tag: %w", err)
+		}
+		if !found {
+			tag = ""
+		}
+	}
+
+	commits, err := git.Commits(tag, "HEAD")
+	if err != nil {
+		return release.Plan{}, fmt.Errorf("list commits: %w", err)
+	}
+	return release.Next(tag, current, commits, rel), nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/RyanTalbot/bartle/internal/branch"
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/RyanTalbot/bartle/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+func PrepareCommitMsgCommand() *cobra.Command {
+	prepareCmd := &cobra.Command{
+		Use:   "prepare-commit-msg <message-file> [source] [sha]",
+		Short: "Pre-fill a commit message from the branch name (prepare-commit-msg hook)",
+		Long: `Run by the prepare-commit-msg hook (bartle install-hook --type prepare-commit-msg)
+with the arguments git passes to it.
+
+The branch.extract patterns in .bartle.yaml pull a ticket key, type and scope
+out of the current branch name, and the configured style turns them into the
+start of the message: "feat(ui): " plus a "Refs: ABC-123" footer for
+conventional, "ABC-123: " for jira.
+
+Merges, squashes, amends, messages given with -m/-F and messages that already
+have content are left alone. Problems never block the commit; they are
+reported and the message is left as it was.`,
+		Example: `
+  bartle prepare-commit-msg .git/COMMIT_EDITMSG`,
+		Args:         cobra.RangeArgs(1, 3),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := ""
+			if len(args) > 1 {
+				source = args[1]
+			}
+			switch source {
+			case "message", "merge", "squash", "commit":
+				return nil
+			}
+
+			if err := prefillMessageFile(args[0]); err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), "⚠️  bartle could not pre-fill the commit message:", err)
+			}
+			return nil
+		},
+	}
+
+	return prepareCmd
+}
+
+func init() {
+	rootCmd.AddCommand(PrepareCommitMsgCommand())
+}
+
+// prefillMessageFile puts the branch-derived start of a message above
+// whatever git wrote to path, unless the message already has content.
+func prefillMessageFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read message file: %w", err)
+	}
+	existing := string(b)
+	if stripGitComments(existing) != "" {
+		return nil
+	}
+
+	cfg, err := loadStagedConfig()
+	if err != nil {
+		return err
+	}
+
+	name, err := git.CurrentBranch()
+	if errors.Is(err, git.ErrDetachedHead) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	info, err := branch.Extract(name, cfg.Branch.Extract)
+	if err != nil {
+		return err
+	}
+	style, ok := lint.LookupStyle(cfg.Style)
+	if !ok || info.Empty() {
+		return nil
+	}
+	prefill := style.Prefill(info, cfg.Rules)
+	if prefill == "" {
+		return nil
+	}
+
+	st, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat message file: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(prefill+"\n"+existing), st.Mode().Perm()); err != nil {
+		return fmt.Errorf("write message file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/RyanTalbot/bartle/internal/branch"
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/RyanTalbot/bartle/internal/release"
+	"github.com/spf13/cobra"
+)
+
+func PrePushCommand() *cobra.Command {
+	prePushCmd := &cobra.Command{
+		Use:   "pre-push <remote> [url]",
+		Short: "Lint outgoing commits and branch names (pre-push hook)",
+		Long: `Run by the pre-push hook (bartle install-hook --type pre-push) with the
+arguments git passes to it and the ref updates on stdin.
+
+Every commit the push would add to the remote is linted with the repo
+config, which catches commits made with --no-verify, by rebases or by other
+clients. For a new remote branch that is every commit not yet on any remote
+and not under the latest version tag (release.tag_prefix). A first push to
+an empty remote from a repository without version tags therefore lints its
+whole history; tag the last release, or push once with --no-verify.
+Every branch being pushed to is also linted like bartle lint-branch does.
+Deletions are skipped. hook.block_on_fail: false reports problems without
+blocking the push.`,
+		Example: `
+  bartle pre-push origin < ref-updates.txt`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadLintConfig()
+			if err != nil {
+				return err
+			}
+
+			updates, err := git.ParsePushUpdates(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			failed := false
+			seen := map[string]bool{}
+			var shas []string
+			for _, u := range updates {
+				if u.Deletes() {
+					continue
+				}
+
+				if name := u.RemoteBranch(); name != "" {
+					problem, err := branch.Lint(name, cfg.Branch)
+					if err != nil {
+						return err
+					}
+					if problem != "" {
+						fmt.Fprintln(out, "❌ Invalid branch name:")
+						fmt.Fprintln(out, " -", problem)
+						failed = true
+					}
+				}
+
+				// Commits under the latest release tag have shipped already, so
+				// a first push to an empty remote doesn't lint the whole history.
+				base, _, _, err := release.LatestTagFrom(cfg.Release.TagPrefix, u.LocalSHA)
+				if err != nil {
+					return fmt.Errorf("find latest tag for %s: %w", u.LocalRef, err)
+				}
+
+				// The same commits often go out under several refs at once.
+				newCommits, err := u.NewCommits(base)
+				if err != nil {
+					return fmt.Errorf("list commits for %s: %w", u.RemoteRef, err)
+				}
+				for _, sha := range newCommits {
+					if !seen[sha] {
+						seen[sha] = true
+						shas = append(shas, sha)
+					}
+				}
+			}
+
+			if len(shas) > 0 {
+				commits, err := git.Load(shas)
+				if err != nil {
+					return fmt.Errorf("read commits: %w", err)
+				}
+				entries, n, err := lintCommits(commits)
+				if err != nil {
+					return err
+				}
+				if n > 0 {
+					printCommitEntries(out, entries)
+					failed = true
+				} else {
+					fmt.Fprintf(out, "✅ %d outgoing commit(s) are valid!\n", len(entries))
+				}
+			}
+
+			if !failed {
+				return nil
+			}
+			if !cfg.Hook.BlockOnFail {
+				fmt.Fprintln(cmd.ErrOrStderr(), "ℹ️  hook.block_on_fail is false, so the push is allowed anyway.")
+				return nil
+			}
+			return errLintFailed
+		},
+	}
+
+	return prePushCmd
+}
+
+func init() {
+	rootCmd.AddCommand(PrePushCommand())
+}
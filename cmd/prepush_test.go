@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPrePushFirstPushStopsAtLatestTag(t *testing.T) {
+	const zero = "0000000000000000000000000000000000000000"
+	initRepo(t)
+	commit(t, "legacy commit from before bartle")
+	runGit(t, "tag", "v1.0.0")
+	commit(t, "feat: add search")
+
+	push := func() (string, error) {
+		cmd := PrePushCommand()
+		cmd.SilenceErrors = true
+		cmd.SetIn(strings.NewReader("refs/heads/main " + runGit(t, "rev-parse", "HEAD") + " refs/heads/main " + zero + "\n"))
+		return executeCommand(cmd, "origin")
+	}
+
+	out, err := push()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "1 outgoing commit(s) are valid!") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	commit(t, "wip")
+	out, err = push()
+	if !errors.Is(err, errLintFailed) || !strings.Contains(out, "wip") || strings.Contains(out, "legacy") {
+		t.Fatalf("error = %v, output:\n%s", err, out)
+	}
+}
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
+	"github.com/RyanTalbot/bartle/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -14,15 +17,37 @@ offer examples when necessary.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+
+	// Errors are printed by Execute so commands can opt out with errLintFailed.
+	SilenceErrors: true,
+
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		config.SetFlags(config.Flags{File: configFile, Set: configSet})
+	},
 }
 
+var (
+	configFile string
+	configSet  []string
+)
+
+// errLintFailed signals a non-zero exit after the command has already
+// printed its own friendly report.
+var errLintFailed = errors.New("lint failed")
+
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
+		if !errors.Is(err, errLintFailed) {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "use this config file instead of the global, repository and nested ones")
+	rootCmd.PersistentFlags().StringArrayVar(&configSet, "set", nil, "override a config value, e.g. --set rules.scope_required=false (repeatable)")
 }
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+func StylesCommand() *cobra.Command {
+	stylesCmd := &cobra.Command{
+		Use:   "styles",
+		Short: "Inspect the commit message styles bartle supports",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List available styles with a description and example",
+		Long: `List every style that can be used as the style key in .bartle.yaml.
+
+Examples are rendered with the default rules (see bartle init).`,
+		Example: `
+  bartle styles list`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rules := config.Default().Rules
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "STYLE\tDESCRIPTION\tEXAMPLE")
+			for _, s := range lint.Styles() {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Name(), s.Description(), s.Example(rules))
+			}
+			return tw.Flush()
+		},
+	}
+
+	stylesCmd.AddCommand(listCmd)
+	return stylesCmd
+}
+
+func init() {
+	rootCmd.AddCommand(StylesCommand())
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/RyanTalbot/bartle/internal/hooks"
 	"github.com/spf13/cobra"
@@ -9,46 +10,59 @@ import (
 
 var (
 	uninstallForce bool
+	uninstallType  string
 )
 
 func UninstallHookCommand() *cobra.Command {
 	var uninstallHookCmd = &cobra.Command{
 		Use:   "uninstall-hook",
-		Short: "Uninstall Bartle's git commit-msg hook",
-		Long: `Removes .git/hooks/commit-msg if it was installed by Bartle.
+		Short: "Uninstall a Bartle git hook",
+		Long: `Removes the commit-msg hook (or the hook named by --type) if it was
+installed by Bartle. Like install-hook it honors core.hooksPath, worktrees
+and submodules.
 
-By default, only removes hooks that contain Bartle's marker.
-Use --force to remove any existing commit-msg hook (a backup is created).`,
+By default, only removes hooks that contain Bartle's marker. A hook that was
+chained with install-hook --chain is put back in place unchanged.
+Use --force to remove any existing hook of that type (a backup is created).`,
 		Example: `
   bartle uninstall-hook
-  bartle uninstall-hook --force`,
+  bartle uninstall-hook --force
+  bartle uninstall-hook --type prepare-commit-msg`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			repoRoot, err := hooks.RepoRootFromCwd()
+			if !hooks.Supported(uninstallType) {
+				return fmt.Errorf("invalid --type %q (allowed: %s)", uninstallType, strings.Join(hooks.Names(), "|"))
+			}
+
+			repo, err := hooks.Locate()
 			if err != nil {
 				return err
 			}
-			hookPath := hooks.HookPath(repoRoot)
+			hookPath := repo.HookPath(uninstallType)
 
-			changed, backupPath, err := hooks.UninstallCommitMsgHook(hookPath, uninstallForce)
+			res, err := hooks.UninstallHook(hookPath, uninstallForce)
 			if err != nil {
 				return err
 			}
-			if !changed {
-				fmt.Fprintln(cmd.OutOrStdout(), "ℹ️  No commit-msg hook to remove.")
+			if !res.Changed {
+				fmt.Fprintf(cmd.OutOrStdout(), "ℹ️  No %s hook to remove.\n", uninstallType)
 				return nil
 			}
 
-			if backupPath != "" {
-				fmt.Fprintln(cmd.OutOrStdout(), "✅ Removed hook. Backup saved at", backupPath)
-			} else {
-				fmt.Fprintln(cmd.OutOrStdout(), "✅ Removed Bartle commit-msg hook.")
+			switch {
+			case res.BackupPath != "":
+				fmt.Fprintln(cmd.OutOrStdout(), "✅ Removed hook. Backup saved at", res.BackupPath)
+			case res.Restored:
+				fmt.Fprintf(cmd.OutOrStdout(), "✅ Removed Bartle %s hook and restored the original.\n", uninstallType)
+			default:
+				fmt.Fprintf(cmd.OutOrStdout(), "✅ Removed Bartle %s hook.\n", uninstallType)
 			}
 			return nil
 		},
 	}
 	uninstallHookCmd.Flags().BoolVarP(&uninstallForce, "force", "f", false, "remove hook even if not installed by Bartle (creates a backup)")
+	uninstallHookCmd.Flags().StringVarP(&uninstallType, "type", "t", hooks.CommitMsg, "hook to remove: "+strings.Join(hooks.Names(), "|"))
 
 	return uninstallHookCmd
 }
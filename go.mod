@@ -3,6 +3,7 @@ module github.com/RyanTalbot/bartle
 go 1.25.0
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/spf13/cobra v1.10.1
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,99 @@
+// Package branch reads what a team's branch naming says about the work on
+// a branch.
+package branch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+)
+
+// Info holds the fields extracted from a branch name. Empty fields weren't
+// found.
+type Info struct {
+	Name   string
+	Type   string
+	Scope  string
+	Ticket string
+}
+
+// Empty reports whether nothing was extracted.
+func (i Info) Empty() bool {
+	return i.Type == "" && i.Scope == "" && i.Ticket == ""
+}
+
+// Extract tries each pattern against name and collects the named groups
+// type, scope and ticket. Patterns are tried in order and the first one to
+// set a group wins.
+func Extract(name string, patterns []string) (Info, error) {
+	info := Info{Name: name}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return info, fmt.Errorf("invalid branch pattern %q: %w", p, err)
+		}
+		m := re.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		for i, group := range re.SubexpNames() {
+			if m[i] == "" {
+				continue
+			}
+			switch group {
+			case "type":
+				setOnce(&info.Type, m[i])
+			case "scope":
+				setOnce(&info.Scope, m[i])
+			case "ticket":
+				setOnce(&info.Ticket, m[i])
+			}
+		}
+	}
+	return info, nil
+}
+
+func setOnce(field *string, v string) {
+	if *field == "" {
+		*field = v
+	}
+}
+
+// Lint checks name against the configured naming rules and returns why it
+// doesn't conform, or "" when it does.
+func Lint(name string, cfg config.Branch) (string, error) {
+	if len(cfg.Prefixes) == 0 && len(cfg.Patterns) == 0 {
+		return "", nil
+	}
+	for _, ignored := range cfg.Ignore {
+		if name == ignored {
+			return "", nil
+		}
+	}
+
+	for _, prefix := range cfg.Prefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return "", nil
+		}
+	}
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return "", fmt.Errorf("invalid branch pattern %q: %w", p, err)
+		}
+		if re.MatchString(name) {
+			return "", nil
+		}
+	}
+
+	var want []string
+	if len(cfg.Prefixes) > 0 {
+		want = append(want, "start with one of: "+strings.Join(cfg.Prefixes, ", "))
+	}
+	if len(cfg.Patterns) > 0 {
+		want = append(want, "match one of: "+strings.Join(cfg.Patterns, ", "))
+	}
+	return fmt.Sprintf("branch %q must %s", name, strings.Join(want, " or ")), nil
+}
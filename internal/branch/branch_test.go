@@ -0,0 +1,75 @@
+package branch
+
+import (
+	"testing"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+)
+
+func TestExtract(t *testing.T) {
+	defaults := config.Default().Branch.Extract
+
+	tests := []struct {
+		name     string
+		branch   string
+		patterns []string
+		want     Info
+	}{
+		{name: "type and ticket", branch: "feature/ABC-123-login", patterns: defaults, want: Info{Type: "feature", Ticket: "ABC-123"}},
+		{name: "ticket only", branch: "ABC-9-fix-it", patterns: defaults, want: Info{Ticket: "ABC-9"}},
+		{name: "nothing", branch: "main", patterns: defaults, want: Info{}},
+		{
+			name:     "first pattern wins",
+			branch:   "fix/ui/ABC-1",
+			patterns: []string{`^(?P<type>[a-z]+)/(?P<scope>[a-z]+)/`, `^(?P<scope>[a-z]+)/`},
+			want:     Info{Type: "fix", Scope: "ui"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract(tt.branch, tt.patterns)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.want.Name = tt.branch
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLint(t *testing.T) {
+	rules := config.Branch{
+		Prefixes: []string{"feat/", "fix/"},
+		Patterns: []string{`^[A-Z]+-[0-9]+/`},
+		Ignore:   []string{"main"},
+	}
+
+	tests := []struct {
+		name   string
+		branch string
+		cfg    config.Branch
+		wantOK bool
+	}{
+		{name: "prefix", branch: "feat/ui-dropdown", cfg: rules, wantOK: true},
+		{name: "pattern", branch: "ABC-123/login", cfg: rules, wantOK: true},
+		{name: "ignored", branch: "main", cfg: rules, wantOK: true},
+		{name: "bare prefix", branch: "feat/", cfg: rules},
+		{name: "no match", branch: "wip", cfg: rules},
+		{name: "no rules", branch: "anything", cfg: config.Branch{}, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem, err := Lint(tt.branch, tt.cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (problem == "") != tt.wantOK {
+				t.Fatalf("Lint(%q) = %q, want ok=%v", tt.branch, problem, tt.wantOK)
+			}
+		})
+	}
+}
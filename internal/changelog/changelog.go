@@ -0,0 +1,115 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/release"
+)
+
+// Title is the heading written at the top of a new changelog file.
+const Title = "# Changelog"
+
+// Release is one section of the changelog.
+type Release struct {
+	Version string
+	Date    time.Time
+	Changes []release.Change
+}
+
+// Render formats a release as Markdown: breaking changes first, then one
+// subsection per configured section, in order.
+func Render(r Release, cfg config.Changelog) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s (%s)\n", r.Version, r.Date.Format("2006-01-02"))
+
+	var breaking []release.Change
+	for _, ch := range r.Changes {
+		if ch.Conventional && ch.Parsed.Breaking {
+			breaking = append(breaking, ch)
+		}
+	}
+	if len(breaking) > 0 {
+		sb.WriteString("\n### ⚠ BREAKING CHANGES\n\n")
+		for _, ch := range breaking {
+			sb.WriteString(entry(ch))
+			if note := ch.Parsed.BreakingNote; note != "" {
+				for _, line := range strings.Split(note, "\n") {
+					sb.WriteString("  " + strings.TrimSpace(line) + "\n")
+				}
+			}
+		}
+	}
+
+	for _, sec := range cfg.Sections {
+		var items []string
+		for _, ch := range r.Changes {
+			if ch.Conventional && containsFold(sec.Types, ch.Parsed.Type) {
+				items = append(items, entry(ch))
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n### %s\n\n", sec.Title)
+		sb.WriteString(strings.Join(items, ""))
+	}
+
+	return sb.String()
+}
+
+func entry(ch release.Change) string {
+	var sb strings.Builder
+	sb.WriteString("- ")
+	if ch.Parsed.Scope != "" {
+		fmt.Fprintf(&sb, "**%s:** ", ch.Parsed.Scope)
+	}
+	fmt.Fprintf(&sb, "%s (%s)\n", ch.Parsed.Subject, ch.Commit.ShortSHA())
+	return sb.String()
+}
+
+func containsFold(list []string, v string) bool {
+	for _, x := range list {
+		if strings.EqualFold(x, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Prepend inserts a rendered release above the newest release in an existing
+// changelog. The title and any hand-written introduction above the first
+// "## " heading are kept as they are, and so is everything below it.
+func Prepend(existing, section, version string) (string, error) {
+	existing = strings.ReplaceAll(existing, "\r\n", "\n")
+	if strings.TrimSpace(existing) == "" {
+		return Title + "\n\n" + section, nil
+	}
+
+	lines := strings.Split(existing, "\n")
+	insertAt := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if heading := strings.TrimPrefix(line, "## "); heading == version || strings.HasPrefix(heading, version+" ") {
+			return "", fmt.Errorf("changelog already has a section for %s", version)
+		}
+		if insertAt < 0 {
+			insertAt = i
+		}
+	}
+
+	if insertAt < 0 {
+		return strings.TrimRight(existing, "\n") + "\n\n" + section, nil
+	}
+
+	head := strings.Join(lines[:insertAt], "\n")
+	tail := strings.Join(lines[insertAt:], "\n")
+	if strings.TrimSpace(head) == "" {
+		return section + "\n" + tail, nil
+	}
+	return strings.TrimRight(head, "\n") + "\n\n" + section + "\n" + tail, nil
+}
@@ -0,0 +1,92 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/RyanTalbot/bartle/internal/release"
+)
+
+func TestRender(t *testing.T) {
+	commits := []git.Commit{
+		{SHA: "1111111aaaa", Message: "feat(ui): add dropdown"},
+		{SHA: "2222222bbbb", Message: "fix: handle nil"},
+		{SHA: "3333333cccc", Message: "docs: typo"},
+		{SHA: "4444444dddd", Message: "feat(api)!: drop v1\n\nBREAKING CHANGE: use /v2"},
+	}
+	got := Render(Release{
+		Version: "v2.0.0",
+		Date:    time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+		Changes: release.Classify(commits, config.Default().Release),
+	}, config.Default().Changelog)
+
+	want := `## v2.0.0 (2026-10-17)
+
+### ⚠ BREAKING CHANGES
+
+- **api:** drop v1 (4444444)
+  use /v2
+
+### Features
+
+- **ui:** add dropdown (1111111)
+- **api:** drop v1 (4444444)
+
+### Bug Fixes
+
+- handle nil (2222222)
+`
+	if got != want {
+		t.Fatalf("output mismatch\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	section := "## v1.1.0 (2026-10-17)\n\n- new\n"
+
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "new file",
+			existing: "",
+			want:     "# Changelog\n\n" + section,
+		},
+		{
+			name:     "keeps title, intro and history",
+			existing: "# Changelog\n\nHand-written notes.\n\n## v1.0.0 (2026-01-01)\n\n- old\n",
+			want:     "# Changelog\n\nHand-written notes.\n\n" + section + "\n## v1.0.0 (2026-01-01)\n\n- old\n",
+		},
+		{
+			name:     "no release headings yet",
+			existing: "# Changelog\n\nNothing released.\n",
+			want:     "# Changelog\n\nNothing released.\n\n" + section,
+		},
+		{
+			name:     "refuses duplicates",
+			existing: "# Changelog\n\n## v1.1.0 (2026-10-01)\n",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Prepend(tt.existing, section, "v1.1.0")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("output mismatch\nwant: %q\ngot:  %q", tt.want, got)
+			}
+			if !tt.wantErr && strings.Count(got, section) != 1 {
+				t.Fatal("section must appear exactly once")
+			}
+		})
+	}
+}
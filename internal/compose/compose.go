@@ -0,0 +1,325 @@
+// Package compose builds conventional commit messages interactively. Every
+// answer is checked with lint.ValidateMessage before moving on, so a
+// finished draft always passes the configured rules.
+package compose
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/lint"
+)
+
+// ErrAborted is returned when input ends before the message is complete.
+var ErrAborted = errors.New("commit aborted")
+
+// Draft is a commit message assembled field by field.
+type Draft struct {
+	Type         string
+	Scope        string
+	Subject      string
+	Body         string
+	Breaking     bool
+	BreakingNote string
+	Footers      []string // "Token: value" lines, in the order entered
+}
+
+// Header renders the first line, e.g. "feat(ui)!: add dropdown".
+func (d Draft) Header() string {
+	var sb strings.Builder
+	sb.WriteString(d.Type)
+	if d.Scope != "" {
+		sb.WriteString("(" + d.Scope + ")")
+	}
+	if d.Breaking {
+		sb.WriteString("!")
+	}
+	sb.WriteString(": ")
+	sb.WriteString(d.Subject)
+	return sb.String()
+}
+
+// Message renders the full commit message. The BREAKING CHANGE footer, if
+// any, comes after the other footers.
+func (d Draft) Message() string {
+	parts := []string{d.Header()}
+	if body := strings.TrimSpace(d.Body); body != "" {
+		parts = append(parts, body)
+	}
+
+	footers := append([]string(nil), d.Footers...)
+	if d.Breaking && d.BreakingNote != "" {
+		footers = append(footers, "BREAKING CHANGE: "+d.BreakingNote)
+	}
+	if len(footers) > 0 {
+		parts = append(parts, strings.Join(footers, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// Prompter asks for each field of a Draft on out and reads answers from in.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	cfg config.Config
+}
+
+func NewPrompter(in io.Reader, out io.Writer, cfg config.Config) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out, cfg: cfg}
+}
+
+// Rules checked after each answer. Anything else is left to the final check.
+var (
+	typeRules     = []string{lint.RuleHeaderFormat, lint.RuleTypeCase, lint.RuleTypeEnum}
+	scopeRules    = []string{lint.RuleHeaderFormat, lint.RuleScopeRequired}
+	subjectRules  = []string{lint.RuleHeaderFormat, lint.RuleHeaderMaxLength, lint.RuleSubjectEmpty, lint.RuleSubjectCase, lint.RuleSubjectSpace, lint.RuleSubjectFullStop}
+	bodyRules     = []string{lint.RuleBodyMaxLineLength}
+	breakingRules = []string{lint.RuleBreakingFooter, lint.RuleBreakingMarker}
+	footerRules   = []string{lint.RuleFooterEmpty}
+)
+
+// placeholderSubject stands in for the subject while earlier fields are
+// checked, so the header parses.
+const placeholderSubject = "subject"
+
+// Run walks through every field and returns a draft that passes validation.
+func (p *Prompter) Run() (Draft, error) {
+	var d Draft
+	rules := p.cfg.Rules
+
+	for {
+		answer, err := p.ask(p.typePrompt())
+		if err != nil {
+			return d, err
+		}
+		d.Type = p.resolveType(answer)
+		if p.accept(d, typeRules) {
+			break
+		}
+	}
+
+	for {
+		label := "Scope (optional)"
+		if rules.ScopeRequired {
+			label = "Scope"
+		}
+		answer, err := p.ask(label + ": ")
+		if err != nil {
+			return d, err
+		}
+		d.Scope = answer
+		if p.accept(d, scopeRules) {
+			break
+		}
+	}
+
+	breaking, err := p.Confirm("Breaking change?", false)
+	if err != nil {
+		return d, err
+	}
+	d.Breaking = breaking
+
+	for {
+		d.Subject = ""
+		prefix := d.Header()
+		label := "Subject: "
+		if rules.MaxLineLength > 0 {
+			label = fmt.Sprintf("Subject (%d chars left): ", rules.MaxLineLength-utf8.RuneCountInString(prefix))
+		}
+		answer, err := p.ask(label)
+		if err != nil {
+			return d, err
+		}
+		d.Subject = answer
+		if p.accept(d, subjectRules) {
+			break
+		}
+	}
+
+	for {
+		body, err := p.askLines("Body (optional, finish with an empty line):")
+		if err != nil {
+			return d, err
+		}
+		d.Body = body
+		if p.accept(d, bodyRules) {
+			break
+		}
+	}
+
+	if d.Breaking {
+		for {
+			label := "Describe the breaking change (optional): "
+			if rules.BreakingFooterRequired {
+				label = "Describe the breaking change: "
+			}
+			answer, err := p.ask(label)
+			if err != nil {
+				return d, err
+			}
+			d.BreakingNote = answer
+			if p.accept(d, breakingRules) {
+				break
+			}
+		}
+	}
+
+	fmt.Fprintln(p.out, "Footers (optional, e.g. Refs: ABC-123), one per line, finish with an empty line:")
+	for {
+		answer, err := p.ask("> ")
+		if err != nil {
+			return d, err
+		}
+		if answer == "" {
+			break
+		}
+		m := lint.ParseMessage("x\n\n" + answer)
+		if len(m.Footers) != 1 {
+			fmt.Fprintln(p.out, "❌ not a footer (expected \"Token: value\" or \"Token #value\")")
+			continue
+		}
+		if lint.IsBreakingToken(m.Footers[0].Token) {
+			fmt.Fprintln(p.out, "❌ answer \"Breaking change?\" instead of adding a BREAKING CHANGE footer")
+			continue
+		}
+		candidate := d
+		candidate.Footers = append(append([]string(nil), d.Footers...), answer)
+		if p.accept(candidate, footerRules) {
+			d = candidate
+		}
+	}
+
+	res := lint.ValidateMessage(d.Message(), p.cfg)
+	if !res.Valid {
+		// Every rule is checked field by field above, so this only trips on
+		// interactions between fields that no single answer could fix.
+		for _, e := range res.Errors() {
+			fmt.Fprintln(p.out, e.String())
+		}
+		return d, fmt.Errorf("composed message does not pass lint")
+	}
+	return d, nil
+}
+
+// Confirm asks a yes/no question; an empty answer picks def.
+func (p *Prompter) Confirm(question string, def bool) (bool, error) {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	for {
+		answer, err := p.ask(question + " " + hint + " ")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "Please answer y or n.")
+	}
+}
+
+func (p *Prompter) typePrompt() string {
+	types := p.cfg.Rules.Types
+	if len(types) == 0 {
+		return "Type: "
+	}
+	var sb strings.Builder
+	sb.WriteString("Type:\n")
+	for i, t := range types {
+		fmt.Fprintf(&sb, "  %d) %s\n", i+1, t)
+	}
+	sb.WriteString("Choose a number or name: ")
+	return sb.String()
+}
+
+// resolveType maps a menu number to its type; anything else is taken as typed.
+func (p *Prompter) resolveType(answer string) string {
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(p.cfg.Rules.Types) {
+		return p.cfg.Rules.Types[n-1]
+	}
+	return answer
+}
+
+// accept validates d and prints the findings for the given rules. It
+// returns false when any of them is an error, so the caller asks again.
+func (p *Prompter) accept(d Draft, rules []string) bool {
+	if d.Subject == "" && !contains(rules, lint.RuleSubjectEmpty) {
+		d.Subject = placeholderSubject
+	}
+
+	ok := true
+	for _, diag := range lint.ValidateMessage(d.Message(), p.cfg).Diagnostics {
+		if !contains(rules, diag.Rule) {
+			continue
+		}
+		if diag.Severity == lint.SeverityError {
+			fmt.Fprintf(p.out, "❌ %s\n", diag.Message)
+			ok = false
+		} else {
+			fmt.Fprintf(p.out, "⚠️  %s\n", diag.Message)
+		}
+	}
+	return ok
+}
+
+// ask prints label and returns one trimmed line of input.
+func (p *Prompter) ask(label string) (string, error) {
+	fmt.Fprint(p.out, label)
+	line, err := p.in.ReadString('\n')
+	switch {
+	case err == nil, errors.Is(err, io.EOF) && line != "":
+		return strings.TrimSpace(line), nil
+	case errors.Is(err, io.EOF):
+		fmt.Fprintln(p.out)
+		return "", ErrAborted
+	default:
+		return "", err
+	}
+}
+
+// askLines reads lines until an empty one. Trailing whitespace is dropped
+// but indentation is kept.
+func (p *Prompter) askLines(label string) (string, error) {
+	fmt.Fprintln(p.out, label)
+	var lines []string
+	for {
+		fmt.Fprint(p.out, "> ")
+		line, err := p.in.ReadString('\n')
+		line = strings.TrimRight(line, " \t\r\n")
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		if line == "" {
+			if errors.Is(err, io.EOF) && len(lines) == 0 {
+				fmt.Fprintln(p.out)
+				return "", ErrAborted
+			}
+			return strings.Join(lines, "\n"), nil
+		}
+		lines = append(lines, line)
+		if errors.Is(err, io.EOF) {
+			return strings.Join(lines, "\n"), nil
+		}
+	}
+}
+
+func contains(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
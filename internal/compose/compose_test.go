@@ -0,0 +1,73 @@
+package compose
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/lint"
+)
+
+func TestPrompterRun(t *testing.T) {
+	strict := config.Default()
+	strict.Rules.LowercaseStart = true
+	strict.Rules.BreakingFooterRequired = true
+
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		input   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "minimal",
+			cfg:   config.Default(),
+			input: "1\nui\n\nadd dropdown\n\n\n",
+			want:  "feat(ui): add dropdown",
+		},
+		{
+			name:  "type by name, body and footers",
+			cfg:   config.Default(),
+			input: "fix\napi\nn\nhandle nil\nit crashed\nbefore\n\nRefs: ABC-1\nnot a footer\n\n",
+			want:  "fix(api): handle nil\n\nit crashed\nbefore\n\nRefs: ABC-1",
+		},
+		{
+			name: "invalid answers are asked again",
+			cfg:  strict,
+			// bad type, missing scope, uppercase subject, missing breaking note
+			input: "feature\n2\n\nui\ny\nHandle nil\nhandle nil\n\n\nuse v2\n\n",
+			want:  "fix(ui)!: handle nil\n\nBREAKING CHANGE: use v2",
+		},
+		{
+			name:    "input ends early",
+			cfg:     config.Default(),
+			input:   "1\n",
+			wantErr: ErrAborted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPrompter(strings.NewReader(tt.input), io.Discard, tt.cfg)
+			d, err := p.Run()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := d.Message(); got != tt.want {
+				t.Fatalf("message mismatch\nwant: %q\ngot:  %q", tt.want, got)
+			}
+			if res := lint.ValidateMessage(d.Message(), tt.cfg); !res.Valid {
+				t.Fatalf("composed message fails lint: %v", res.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PresetPrefix marks an extends entry naming a built-in preset,
+// e.g. bartle:strict.
+const PresetPrefix = "bartle:"
+
+//go:embed presets/*.yaml
+var presets embed.FS
+
+// Presets returns the names of the built-in presets, sorted.
+func Presets() []string {
+	entries, _ := fs.ReadDir(presets, "presets")
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StringList is a YAML string or list of strings.
+type StringList []string
+
+func (s *StringList) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		if n.Tag == "!!null" {
+			*s = nil
+		} else {
+			*s = StringList{n.Value}
+		}
+		return nil
+	}
+	var list []string
+	if err := n.Decode(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// Origin is where a config value was set.
+type Origin struct {
+	File string
+	Line int
+}
+
+func (o Origin) String() string {
+	if o.Line == 0 {
+		return o.File
+	}
+	return fmt.Sprintf("%s:%d", o.File, o.Line)
+}
+
+// Sources maps dotted keys (rules.max_line_length, branch.patterns[1],
+// rules.severity.subject-case) to the file that last set them. Keys that
+// aren't present still hold their default.
+type Sources map[string]Origin
+
+// Lookup returns the origin of key, or of the nearest parent that has one.
+func (s Sources) Lookup(key string) (Origin, bool) {
+	for key != "" {
+		if o, ok := s[key]; ok {
+			return o, true
+		}
+		i := strings.LastIndexAny(key, ".[")
+		if i < 0 {
+			break
+		}
+		key = key[:i]
+	}
+	return Origin{}, false
+}
+
+// Locate fills in where p's field was set, when p doesn't say already.
+func (s Sources) Locate(p Problem) Problem {
+	if p.Origin.File == "" {
+		p.Origin, _ = s.Lookup(p.Field)
+	}
+	return p
+}
+
+// record notes the origin of every key under root.
+func (s Sources) record(file string, root *yaml.Node) {
+	s.walk(file, "", root)
+}
+
+func (s Sources) walk(file, key string, n *yaml.Node) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		// Maps merge, so keys set by earlier files survive.
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if key == "" && k.Value == "extends" {
+				continue
+			}
+			child := k.Value
+			if key != "" {
+				child = key + "." + k.Value
+			}
+			s.forget(child)
+			s[child] = Origin{File: file, Line: k.Line}
+			s.walk(file, child, v)
+		}
+	case yaml.SequenceNode:
+		// Lists replace, so s.forget has already dropped the old elements.
+		for i, el := range n.Content {
+			child := fmt.Sprintf("%s[%d]", key, i)
+			s[child] = Origin{File: file, Line: el.Line}
+			s.walk(file, child, el)
+		}
+	}
+}
+
+// forget drops the recorded elements of a list about to be replaced.
+func (s Sources) forget(key string) {
+	for k := range s {
+		if strings.HasPrefix(k, key+"[") {
+			delete(s, k)
+		}
+	}
+}
+
+// loader applies config files on top of the defaults, resolving extends as
+// it goes. Problems are collected rather than returned: a file that can't be
+// read or parsed is skipped and the rest still apply.
+type loader struct {
+	root     string // repo root, for vendored extends and display names
+	cfg      Config
+	sources  Sources
+	problems []Problem
+	stack    []string // files being applied, to catch cycles
+
+	// version and migrations upgrade older files before they are decoded.
+	version    int
+	migrations map[int]migration
+}
+
+func newLoader(root string) *loader {
+	return &loader{root: root, cfg: Default(), sources: Sources{}, version: CurrentVersion, migrations: migrations}
+}
+
+func (l *loader) problem(file, format string, args ...any) {
+	l.problems = append(l.problems, Problem{Origin: Origin{File: file}, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *loader) applyFile(path string) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		l.problem(l.display(path), "read config: %v", err)
+		return
+	}
+	l.apply(filepath.Clean(path), normalize(raw))
+}
+
+// apply applies the bases named in extends, in order, then the file itself.
+// A base extended twice along different paths is simply applied twice; only
+// a file extending itself, directly or not, is an error.
+func (l *loader) apply(name string, raw []byte) {
+	display := l.display(name)
+	for i, s := range l.stack {
+		if s == name {
+			var cycle []string
+			for _, f := range l.stack[i:] {
+				cycle = append(cycle, l.display(f))
+			}
+			cycle = append(cycle, display)
+			l.problem(l.display(l.stack[len(l.stack)-1]), "extends cycle: %s", strings.Join(cycle, " -> "))
+			return
+		}
+	}
+	l.stack = append(l.stack, name)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	root, err := parseConfig(name, raw)
+	if err != nil {
+		l.problems = append(l.problems, yamlProblems(display, err)...)
+		return
+	}
+	if root == nil {
+		return // an empty file changes nothing
+	}
+
+	// Older versions are upgraded in memory; only a newer one can't be read.
+	from, line, err := fileVersion(root, l.version)
+	if err != nil {
+		l.problems = append(l.problems, Problem{Origin: Origin{File: display, Line: line}, Field: "version", Message: err.Error()})
+		return
+	}
+	if err := upgrade(root, from, l.version, l.migrations); err != nil {
+		l.problem(display, "%v", err)
+		return
+	}
+	var head struct {
+		Extends StringList `yaml:"extends"`
+	}
+	_ = root.Decode(&head) // the full decode below reports a malformed extends
+
+	for _, ref := range head.Extends {
+		base, data, err := l.resolve(name, ref)
+		if err != nil {
+			l.problem(display, "extends %q: %v", ref, err)
+			continue
+		}
+		l.apply(base, data)
+	}
+
+	l.problems = append(l.problems, checkKeys(display, root, reflect.TypeOf(Config{}), "")...)
+	if err := root.Decode(&l.cfg); err != nil {
+		l.problems = append(l.problems, yamlProblems(display, err)...)
+	}
+	l.cfg.Extends = nil
+	l.cfg.Version = CurrentVersion
+	l.sources.record(display, root)
+}
+
+// resolve finds the config an extends entry refers to:
+//
+//	bartle:<name>   a built-in preset
+//	/abs, ./rel     a file, relative to the extending file
+//	anything else   a file under the repo's vendor directory
+func (l *loader) resolve(from, ref string) (string, []byte, error) {
+	if name, ok := strings.CutPrefix(ref, PresetPrefix); ok {
+		data, err := presets.ReadFile(path.Join("presets", name+".yaml"))
+		if err != nil {
+			return "", nil, fmt.Errorf("unknown preset (available: %s)", strings.Join(Presets(), ", "))
+		}
+		return ref, normalize(data), nil
+	}
+	if strings.HasPrefix(from, PresetPrefix) {
+		return "", nil, fmt.Errorf("presets can only extend other presets")
+	}
+
+	var p string
+	switch {
+	case filepath.IsAbs(ref):
+		p = ref
+	case strings.HasPrefix(ref, "./"), strings.HasPrefix(ref, "../"):
+		p = filepath.Join(filepath.Dir(from), filepath.FromSlash(ref))
+	default:
+		p = filepath.Join(l.root, "vendor", filepath.FromSlash(ref))
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return "", nil, err
+	}
+	return filepath.Clean(p), normalize(data), nil
+}
+
+// display shortens files inside the repo to repo-relative paths.
+func (l *loader) display(name string) string {
+	if l.root == "" || strings.HasPrefix(name, PresetPrefix) {
+		return name
+	}
+	if rel, err := filepath.Rel(l.root, name); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return name
+}
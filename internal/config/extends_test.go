@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadExtends(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		check   func(t *testing.T, cfg Config, src Sources)
+		wantErr string
+	}{
+		{
+			name: "preset then repo overrides",
+			files: map[string]string{
+				FileName: "extends: bartle:strict\nrules:\n  max_line_length: 100\n",
+			},
+			check: func(t *testing.T, cfg Config, src Sources) {
+				if !cfg.Rules.LowercaseStart || cfg.Rules.MaxLineLength != 100 {
+					t.Fatalf("lowercase_start=%v max_line_length=%d", cfg.Rules.LowercaseStart, cfg.Rules.MaxLineLength)
+				}
+				if o := src["rules.lowercase_start"]; o.File != "bartle:strict" {
+					t.Fatalf("rules.lowercase_start from %v", o)
+				}
+				if o := src["rules.max_line_length"]; o != (Origin{File: FileName, Line: 3}) {
+					t.Fatalf("rules.max_line_length from %v", o)
+				}
+				if cfg.Extends != nil {
+					t.Fatalf("extends left in config: %v", cfg.Extends)
+				}
+			},
+		},
+		{
+			name: "chain of relative and vendored files in order",
+			files: map[string]string{
+				FileName:                 "extends: [./ci/base.yaml, org/bartle.yaml]\nrules:\n  severity:\n    subject-case: off\n",
+				"ci/base.yaml":           "extends: ../vendor/org/root.yaml\nrules:\n  scope_required: false\n  max_line_length: 60\n",
+				"vendor/org/root.yaml":   "rules:\n  severity:\n    body-max-line-length: warn\n",
+				"vendor/org/bartle.yaml": "rules:\n  max_line_length: 80\n",
+			},
+			check: func(t *testing.T, cfg Config, src Sources) {
+				if cfg.Rules.ScopeRequired || cfg.Rules.MaxLineLength != 80 {
+					t.Fatalf("scope_required=%v max_line_length=%d", cfg.Rules.ScopeRequired, cfg.Rules.MaxLineLength)
+				}
+				if len(cfg.Rules.Severity) != 2 {
+					t.Fatalf("severity maps not merged: %v", cfg.Rules.Severity)
+				}
+				if o := src["rules.max_line_length"]; o.File != "vendor/org/bartle.yaml" {
+					t.Fatalf("rules.max_line_length from %v", o)
+				}
+			},
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				FileName: "extends: ./a.yaml\n",
+				"a.yaml": "extends: ./b.yaml\n",
+				"b.yaml": "extends: ./a.yaml\n",
+			},
+			wantErr: "extends cycle: a.yaml -> b.yaml -> a.yaml",
+		},
+		{
+			name: "base from a newer bartle",
+			files: map[string]string{
+				FileName:    "version: 1\nextends: ./base.yaml\n",
+				"base.yaml": "# comment\nversion: 2\n",
+			},
+			wantErr: "base.yaml:2: version: config version is newer than this bartle supports",
+		},
+		{
+			name:    "unknown preset",
+			files:   map[string]string{FileName: "extends: bartle:nope\n"},
+			wantErr: `extends "bartle:nope": unknown preset`,
+		},
+		{
+			name: "bad value names its file and line",
+			files: map[string]string{
+				FileName:    "extends: ./base.yaml\n",
+				"base.yaml": "style: jira\nbranch:\n  patterns:\n    - '^ok$'\n    - '('\n",
+			},
+			wantErr: "base.yaml:5: branch.patterns[1]: not a valid regular expression",
+		},
+		{
+			name: "unknown field in a base",
+			files: map[string]string{
+				FileName:    "extends: ./base.yaml\n",
+				"base.yaml": "rules:\n  max_len: 10\n",
+			},
+			wantErr: "base.yaml:2: rules.max_len: unknown key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Chdir(root)
+			if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(root, name), content)
+			}
+
+			if tt.wantErr != "" {
+				_, _, err := Load()
+				if !errors.Is(err, ErrConfigMalformed) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			r, err := Inspect(nil)
+			if err != nil || len(r.Problems) > 0 {
+				t.Fatalf("unexpected error: %v %v", err, r.Problems)
+			}
+			tt.check(t, r.Config, r.Sources)
+		})
+	}
+}
+
+func TestPresetsAreValid(t *testing.T) {
+	for _, name := range Presets() {
+		l := newLoader("")
+		data, err := presets.ReadFile("presets/" + name + ".yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.apply(PresetPrefix+name, data)
+		if problems := append(l.problems, validate(l.cfg)...); len(problems) > 0 {
+			t.Fatalf("%s: %v", name, problems)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the config files looked for in the repo root and in nested
+// directories, in order of preference. package.json only counts when it has
+// a top-level PackageKey.
+var FileNames = []string{FileName, ".bartle.json", ".bartle.toml", "package.json"}
+
+// PackageKey is the package.json key holding the config.
+const PackageKey = "bartle"
+
+// configsIn returns the config files in dir, in FileNames order.
+func configsIn(dir string) []string {
+	var found []string
+	for _, name := range FileNames {
+		p := filepath.Join(dir, name)
+		if !fileExists(p) {
+			continue
+		}
+		if name == "package.json" && !hasPackageKey(p) {
+			continue
+		}
+		found = append(found, p)
+	}
+	return found
+}
+
+// preferredConfig is the config file used for dir, or FileName when there
+// is none.
+func preferredConfig(dir string) string {
+	if found := configsIn(dir); len(found) > 0 {
+		return found[0]
+	}
+	return filepath.Join(dir, FileName)
+}
+
+// checkAmbiguous reports other config files next to the one in use, outside
+// of --config: which one wins is easy to miss, so it shouldn't matter.
+func (l *loader) checkAmbiguous(used string) {
+	if flags.File != "" {
+		return
+	}
+	for _, other := range configsIn(filepath.Dir(used)) {
+		if other != used {
+			l.problem(l.display(other), "also configures bartle; keep only %s", filepath.Base(used))
+		}
+	}
+}
+
+func hasPackageKey(path string) bool {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var pkg map[string]json.RawMessage
+	if json.Unmarshal(raw, &pkg) != nil {
+		return false
+	}
+	_, ok := pkg[PackageKey]
+	return ok
+}
+
+// parseConfig reads a config file of any supported format into a YAML node,
+// so every format shares one decoder, strictness check and source tracking.
+// root is nil for an empty file.
+func parseConfig(name string, raw []byte) (root *yaml.Node, err error) {
+	switch {
+	case filepath.Base(name) == "package.json":
+		root, err = parseJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		_, v := mappingEntry(root, PackageKey)
+		return v, nil
+	case strings.HasSuffix(name, ".json"):
+		return parseJSON(raw)
+	case strings.HasSuffix(name, ".toml"):
+		return parseTOML(raw)
+	default:
+		var doc yaml.Node
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		if len(doc.Content) == 0 {
+			return nil, nil
+		}
+		return doc.Content[0], nil
+	}
+}
+
+// parseJSON converts a JSON document to nodes, keeping key order and the line
+// each key and value starts on. As with encoding/json, a repeated key's last
+// value wins.
+func parseJSON(raw []byte) (*yaml.Node, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, nil
+	}
+	p := jsonParser{raw: raw, dec: json.NewDecoder(bytes.NewReader(raw))}
+	p.dec.UseNumber()
+	root, err := p.value()
+	if err == nil {
+		if _, err = p.token(); err == nil {
+			err = p.errorf("unexpected data after the top-level value")
+		} else if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		var se *json.SyntaxError
+		if errors.As(err, &se) {
+			return nil, fmt.Errorf("line %d: %v", p.line(se.Offset), se)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("line %d: unexpected end of JSON input", p.line(int64(len(raw))))
+		}
+		return nil, err
+	}
+	return root, nil
+}
+
+type jsonParser struct {
+	raw []byte
+	dec *json.Decoder
+	pos int64 // offset where the last token starts
+}
+
+// token reads the next token, noting where it starts.
+func (p *jsonParser) token() (json.Token, error) {
+	start := p.dec.InputOffset()
+	t, err := p.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	// start is where the previous token ended; skip the whitespace and
+	// separator after it to reach this one.
+	p.pos = start
+	for p.pos < int64(len(p.raw)) && strings.IndexByte(" \t\r\n,:", p.raw[p.pos]) >= 0 {
+		p.pos++
+	}
+	return t, nil
+}
+
+func (p *jsonParser) line(offset int64) int {
+	if offset > int64(len(p.raw)) {
+		offset = int64(len(p.raw))
+	}
+	return bytes.Count(p.raw[:offset], []byte("\n")) + 1
+}
+
+func (p *jsonParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line(p.pos), fmt.Sprintf(format, args...))
+}
+
+func (p *jsonParser) value() (*yaml.Node, error) {
+	t, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	line := p.line(p.pos)
+	switch t := t.(type) {
+	case json.Delim:
+		if t == '[' {
+			n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: line}
+			for p.dec.More() {
+				el, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				n.Content = append(n.Content, el)
+			}
+			_, err := p.token() // ]
+			return n, err
+		}
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line}
+		seen := map[string]int{}
+		for p.dec.More() {
+			kt, err := p.token()
+			if err != nil {
+				return nil, err
+			}
+			k := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: kt.(string), Line: p.line(p.pos)}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			if i, dup := seen[k.Value]; dup {
+				n.Content[i], n.Content[i+1] = k, v
+				continue
+			}
+			seen[k.Value] = len(n.Content)
+			n.Content = append(n.Content, k, v)
+		}
+		_, err := p.token() // }
+		return n, err
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t, Line: line}, nil
+	case json.Number:
+		tag := "!!float"
+		if _, err := t.Int64(); err == nil {
+			tag = "!!int"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String(), Line: line}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(t), Line: line}, nil
+	default: // nil
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null", Line: line}, nil
+	}
+}
+
+// parseTOML converts a TOML document to nodes in the order its keys appear.
+// The TOML library doesn't expose where keys are, so the nodes carry no
+// line numbers.
+func parseTOML(raw []byte) (*yaml.Node, error) {
+	var data map[string]any
+	md, err := toml.Decode(string(raw), &data)
+	if err != nil {
+		var pe toml.ParseError
+		if errors.As(err, &pe) {
+			return nil, fmt.Errorf("line %d: %s", pe.Position.Line, pe.Message)
+		}
+		return nil, err
+	}
+	order := map[string]int{}
+	for i, k := range md.Keys() {
+		order[k.String()] = i
+	}
+	return tomlNode(data, nil, order), nil
+}
+
+func tomlNode(v any, key toml.Key, order map[string]int) *yaml.Node {
+	switch v := v.(type) {
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		pos := func(name string) int {
+			return order[append(append(toml.Key(nil), key...), name).String()]
+		}
+		sort.SliceStable(names, func(i, j int) bool { return pos(names[i]) < pos(names[j]) })
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, name := range names {
+			child := append(append(toml.Key(nil), key...), name)
+			n.Content = append(n.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name},
+				tomlNode(v[name], child, order))
+		}
+		return n
+	case []map[string]any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, el := range v {
+			n.Content = append(n.Content, tomlNode(el, key, order))
+		}
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, el := range v {
+			n.Content = append(n.Content, tomlNode(el, key, order))
+		}
+		return n
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+	case int64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(v, 10)}
+	case float64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: strconv.FormatFloat(v, 'g', -1, 64)}
+	case time.Time:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: v.Format(time.RFC3339Nano)}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(v)}
+	}
+}
+
+// checkKeys reports keys that don't exist in t, the strictness KnownFields
+// gives a YAML decoder, for nodes from any format.
+func checkKeys(file string, n *yaml.Node, t reflect.Type, key string) []Problem {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if t == stringListType {
+		return nil
+	}
+
+	var out []Problem
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			return nil // the decoder reports the type mismatch
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Tag == "!!merge" {
+				out = append(out, checkKeys(file, v, t, key)...)
+				continue
+			}
+			child := k.Value
+			if key != "" {
+				child = key + "." + k.Value
+			}
+			f, ok := fieldTypeByTag(t, k.Value)
+			if !ok {
+				out = append(out, Problem{Origin: Origin{File: file, Line: k.Line}, Field: child, Message: "unknown key"})
+				continue
+			}
+			out = append(out, checkKeys(file, v, f, child)...)
+		}
+	case reflect.Map:
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				out = append(out, checkKeys(file, n.Content[i+1], t.Elem(), key+"."+n.Content[i].Value)...)
+			}
+		}
+	case reflect.Slice:
+		if n.Kind == yaml.SequenceNode {
+			for i, el := range n.Content {
+				out = append(out, checkKeys(file, el, t.Elem(), fmt.Sprintf("%s[%d]", key, i))...)
+			}
+		}
+	}
+	return out
+}
+
+func fieldTypeByTag(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if tag == name {
+			return t.Field(i).Type, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		maxLen   int
+		from     string // origin of rules.max_line_length
+		problems []string
+	}{
+		{
+			name:   "json",
+			files:  map[string]string{".bartle.json": "{\n\t\"rules\": {\n\t\t\"max_line_length\": 60\n\t}\n}\n"},
+			maxLen: 60,
+			from:   ".bartle.json:3",
+		},
+		{
+			name:   "toml",
+			files:  map[string]string{".bartle.toml": "extends = \"bartle:strict\"\n\n[rules]\nmax_line_length = 61\n\n[[changelog.sections]]\ntitle = \"Fixes\"\ntypes = [\"fix\"]\n"},
+			maxLen: 61,
+			from:   ".bartle.toml",
+		},
+		{
+			name:   "package.json",
+			files:  map[string]string{"package.json": "{\n  \"name\": \"web\",\n  \"bartle\": {\n    \"rules\": {\"max_line_length\": 62}\n  }\n}\n"},
+			maxLen: 62,
+			from:   "package.json:4",
+		},
+		{
+			name: "package.json without a bartle key is ignored",
+			files: map[string]string{
+				"package.json": `{"name": "web"}`,
+				".bartle.toml": "[rules]\nmax_line_length = 63\n",
+			},
+			maxLen: 63,
+			from:   ".bartle.toml",
+		},
+		{
+			name: "lookup order picks yaml and flags the rest",
+			files: map[string]string{
+				FileName:       "rules:\n  max_line_length: 64\n",
+				".bartle.json": `{}`,
+				"package.json": `{"bartle": {}}`,
+			},
+			maxLen: 64,
+			from:   ".bartle.yaml:2",
+			problems: []string{
+				".bartle.json: also configures bartle; keep only .bartle.yaml",
+				"package.json: also configures bartle; keep only .bartle.yaml",
+			},
+		},
+		{
+			name:   "unknown keys in json",
+			files:  map[string]string{".bartle.json": "{\n  \"rules\": {\"max_len\": 1},\n  \"changelog\": {\"sections\": [{\"name\": \"x\"}]}\n}\n"},
+			maxLen: 72,
+			problems: []string{
+				".bartle.json:2: rules.max_len: unknown key",
+				".bartle.json:3: changelog.sections[0].name: unknown key",
+				".bartle.json:3: changelog.sections[0]: title and types are required",
+			},
+		},
+		{
+			name:     "unknown keys in toml",
+			files:    map[string]string{".bartle.toml": "[rules]\nmax_len = 1\n"},
+			maxLen:   72,
+			problems: []string{".bartle.toml: rules.max_len: unknown key"},
+		},
+		{
+			name:     "json syntax error",
+			files:    map[string]string{".bartle.json": "{\n  \"rules\": {,}\n}\n"},
+			maxLen:   72,
+			problems: []string{".bartle.json:2: invalid character ','"},
+		},
+		{
+			name:     "toml syntax error",
+			files:    map[string]string{".bartle.toml": "[rules]\nmax_line_length = \n"},
+			maxLen:   72,
+			problems: []string{".bartle.toml:2: "},
+		},
+		{
+			name:     "toml type error",
+			files:    map[string]string{".bartle.toml": "[rules]\nmax_line_length = \"long\"\n"},
+			maxLen:   72,
+			problems: []string{".bartle.toml: cannot unmarshal !!str `long` into int"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Chdir(root)
+			if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(root, name), content)
+			}
+
+			r, err := Inspect(nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(r.Problems) != len(tt.problems) {
+				t.Fatalf("problems = %v, want %v", r.Problems, tt.problems)
+			}
+			for i, p := range r.Problems {
+				if !strings.HasPrefix(p.Error(), tt.problems[i]) {
+					t.Errorf("problem %d = %q, want prefix %q", i, p.Error(), tt.problems[i])
+				}
+			}
+			if r.Config.Rules.MaxLineLength != tt.maxLen {
+				t.Errorf("max_line_length = %d, want %d", r.Config.Rules.MaxLineLength, tt.maxLen)
+			}
+			if tt.from != "" {
+				if o := r.Sources["rules.max_line_length"]; o.String() != tt.from {
+					t.Errorf("rules.max_line_length from %v, want %s", o, tt.from)
+				}
+			}
+		})
+	}
+}
+
+func TestNestedPackageJSON(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(root)
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, FileName), "rules:\n  max_line_length: 60\n")
+	writeFile(t, filepath.Join(root, "web", "package.json"), `{"bartle": {"rules": {"max_line_length": 100}}}`)
+	writeFile(t, filepath.Join(root, "api", "package.json"), `{"name": "api"}`)
+
+	for paths, want := range map[string]int{"web/src/app.ts": 100, "api/index.js": 60} {
+		cfg, _, err := LoadFor([]string{paths})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", paths, err)
+		}
+		if cfg.Rules.MaxLineLength != want {
+			t.Errorf("%s: max_line_length = %d, want %d", paths, cfg.Rules.MaxLineLength, want)
+		}
+	}
+}
+
+// Valid JSON that a YAML parser rejects must still load.
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		pattern string
+		maxLen  int
+		line    int // of rules.max_line_length's key, when set
+		wantErr string
+	}{
+		{
+			name:    "escaped slash",
+			in:      `{"rules":{"pattern":"^[a-z]+\/x: .+$"}}`,
+			pattern: "^[a-z]+/x: .+$",
+		},
+		{
+			name:    "surrogate pair",
+			in:      `{"rules":{"pattern":"^\ud83d\ude00 .+$"}}`,
+			pattern: "^\U0001F600 .+$",
+		},
+		{
+			name:   "duplicate keys, last wins",
+			in:     "{\n  \"rules\": {\n    \"max_line_length\": 50,\n    \"max_line_length\": 90\n  }\n}\n",
+			maxLen: 90,
+			line:   4,
+		},
+		{
+			name:    "trailing value",
+			in:      "{}\n{}\n",
+			wantErr: "line 2: unexpected data after the top-level value",
+		},
+		{
+			name:    "truncated",
+			in:      "{\n  \"rules\": {\n",
+			wantErr: "line 3: unexpected end of JSON input",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := parseJSON([]byte(tt.in))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var cfg Config
+			if err := root.Decode(&cfg); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if cfg.Rules.Pattern != tt.pattern || cfg.Rules.MaxLineLength != tt.maxLen {
+				t.Fatalf("pattern = %q, max_line_length = %d", cfg.Rules.Pattern, cfg.Rules.MaxLineLength)
+			}
+			if tt.line != 0 {
+				src := Sources{}
+				src.record(".bartle.json", root)
+				if o := src["rules.max_line_length"]; o.Line != tt.line {
+					t.Fatalf("rules.max_line_length from line %d, want %d", o.Line, tt.line)
+				}
+			}
+		})
+	}
+}
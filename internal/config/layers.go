@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileName is the default name of the repo config and of nested
+// per-directory configs; FileNames lists the alternatives.
+const FileName = ".bartle.yaml"
+
+// GlobalPath returns the user-level config file,
+// $XDG_CONFIG_HOME/bartle/config.yaml (~/.config/bartle/config.yaml when
+// XDG_CONFIG_HOME is unset). ok is false when no home directory is known.
+func GlobalPath() (string, bool) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "bartle", "config.yaml"), true
+}
+
+// nestedConfigs returns the config files below root in the
+// directories containing paths or any of their parents, shallowest first.
+// Ties are broken by path, so among siblings the last in path order wins.
+func nestedConfigs(root string, paths []string) []string {
+	seen := map[string]bool{}
+	var found []string
+	for _, p := range paths {
+		dir := filepath.Dir(filepath.FromSlash(p))
+		for dir != "." && dir != string(filepath.Separator) && !seen[dir] {
+			seen[dir] = true
+			if f := configsIn(filepath.Join(root, dir)); len(f) > 0 {
+				found = append(found, f[0])
+			}
+			dir = filepath.Dir(dir)
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		di, dj := depth(found[i]), depth(found[j])
+		if di != dj {
+			return di < dj
+		}
+		return found[i] < found[j]
+	})
+	return found
+}
+
+func depth(path string) int {
+	return strings.Count(filepath.ToSlash(path), "/")
+}
+
+func fileExists(path string) bool {
+	st, err := os.Stat(path)
+	return err == nil && !st.IsDir()
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadForPrecedence(t *testing.T) {
+	root := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Chdir(root)
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := Load(); !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("Load without any config: err = %v, want ErrConfigNotFound", err)
+	}
+
+	writeFile(t, filepath.Join(xdg, "bartle", "config.yaml"), "rules:\n  max_line_length: 50\n  lowercase_start: true\n")
+	writeFile(t, filepath.Join(root, FileName), "rules:\n  max_line_length: 60\n")
+	writeFile(t, filepath.Join(root, "svc", FileName), "rules:\n  scope_required: false\n  max_line_length: 80\n")
+	writeFile(t, filepath.Join(root, "svc", "api", FileName), "rules:\n  max_line_length: 90\n")
+	writeFile(t, filepath.Join(root, "svc", "web", FileName), "rules:\n  max_line_length: 100\n  scope_required: true\n")
+
+	tests := []struct {
+		name          string
+		paths         []string
+		maxLen        int
+		scopeRequired bool
+	}{
+		{name: "repo over global", paths: nil, maxLen: 60, scopeRequired: true},
+		{name: "unrelated paths", paths: []string{"docs/readme.md"}, maxLen: 60, scopeRequired: true},
+		{name: "nested", paths: []string{"svc/main.go"}, maxLen: 80, scopeRequired: false},
+		{name: "deeper wins", paths: []string{"svc/api/handler.go", "svc/main.go"}, maxLen: 90, scopeRequired: false},
+		{name: "siblings apply in path order", paths: []string{"svc/web/app.ts", "svc/api/handler.go"}, maxLen: 100, scopeRequired: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _, err := LoadFor(tt.paths)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.Rules.MaxLineLength != tt.maxLen || cfg.Rules.ScopeRequired != tt.scopeRequired {
+				t.Fatalf("max_line_length=%d scope_required=%v, want %d %v",
+					cfg.Rules.MaxLineLength, cfg.Rules.ScopeRequired, tt.maxLen, tt.scopeRequired)
+			}
+			if !cfg.Rules.LowercaseStart {
+				t.Fatal("global lowercase_start was lost")
+			}
+		})
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -18,11 +22,48 @@ type AI struct {
 	Temperature float64 `yaml:"temperature"`
 }
 
+// APIKeyFromEnv is the api_key prefix that names an environment variable
+// holding the key, e.g. env:OPENAI_API_KEY.
+const APIKeyFromEnv = "env:"
+
+// ResolveAPIKey returns the API key, reading it from the environment when
+// api_key uses the env: form.
+func (a AI) ResolveAPIKey() (string, error) {
+	name, fromEnv := strings.CutPrefix(a.APIKey, APIKeyFromEnv)
+	if !fromEnv {
+		if a.APIKey == "" {
+			return "", errors.New("ai.api_key is empty")
+		}
+		return a.APIKey, nil
+	}
+	key := os.Getenv(name)
+	if key == "" {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return key, nil
+}
+
 type Rules struct {
-	ScopeRequired  bool     `yaml:"scope_required"`
-	MaxLineLength  int      `yaml:"max_line_length"`
-	LowercaseStart bool     `yaml:"lowercase_start"`
-	Types          []string `yaml:"types"`
+	ScopeRequired     bool     `yaml:"scope_required"`
+	MaxLineLength     int      `yaml:"max_line_length"`
+	BodyMaxLineLength int      `yaml:"body_max_line_length"`
+	LowercaseStart    bool     `yaml:"lowercase_start"`
+	Types             []string `yaml:"types"`
+
+	// Pattern is a regular expression the header must match. It drives the
+	// custom style, where named groups (type, scope, ticket, subject,
+	// breaking) feed the other rules, and further restricts the jira style.
+	Pattern string `yaml:"pattern"`
+
+	// Severity overrides the severity of individual rules by ID,
+	// e.g. {subject-case: warn, body-max-line-length: off}.
+	Severity map[string]string `yaml:"severity"`
+
+	// BreakingFooterRequired makes a `!` marker without a BREAKING CHANGE
+	// footer an error; BreakingMarkerRequired does the reverse. Enable both
+	// to require that the two always agree.
+	BreakingFooterRequired bool `yaml:"breaking_footer_required"`
+	BreakingMarkerRequired bool `yaml:"breaking_marker_required"`
 }
 
 type Hook struct {
@@ -30,11 +71,62 @@ type Hook struct {
 	BlockOnFail bool `yaml:"block_on_fail"`
 }
 
+// Release drives `bartle next-version`.
+type Release struct {
+	// TagPrefix is stripped from tags before parsing them as versions.
+	TagPrefix string `yaml:"tag_prefix"`
+	// Bumps maps a commit type to major|minor|patch|none. Breaking changes
+	// always bump major; unlisted types don't bump.
+	Bumps map[string]string `yaml:"bumps"`
+}
+
+// ChangelogSection groups commit types under one heading.
+type ChangelogSection struct {
+	Title string   `yaml:"title"`
+	Types []string `yaml:"types"`
+}
+
+// Changelog drives `bartle changelog`. Sections are rendered in order;
+// commit types not listed in any section are left out (breaking changes are
+// always listed).
+type Changelog struct {
+	Sections []ChangelogSection `yaml:"sections"`
+}
+
+// Branch describes the team's branch naming.
+type Branch struct {
+	// A branch name is valid when it starts with one of Prefixes or matches
+	// one of Patterns. With neither set, any name is allowed.
+	Prefixes []string `yaml:"prefixes"`
+	Patterns []string `yaml:"patterns"`
+	// Ignore lists branch names exempt from the rules, e.g. main.
+	Ignore []string `yaml:"ignore"`
+
+	// Extract holds regexes tried against the branch name by the
+	// prepare-commit-msg hook. Named groups ticket, type and scope pre-fill
+	// the commit message; when several patterns set the same group, the
+	// first match wins.
+	Extract []string `yaml:"extract"`
+}
+
 type Config struct {
-	Style string `yaml:"style"`
-	AI    AI     `yaml:"ai"`
-	Rules Rules  `yaml:"rules"`
-	Hook  Hook   `yaml:"hook"`
+	// Version is the schema version of the file, 1 when absent. Loaded
+	// configs are always CurrentVersion.
+	Version int `yaml:"version,omitempty"`
+
+	// Extends names base configs applied before this file: built-in presets
+	// (bartle:strict), paths relative to this file (./base.yaml) or paths
+	// under the repo's vendor directory (org-config/bartle.yaml). The loader
+	// resolves it, so it is always empty after Load.
+	Extends StringList `yaml:"extends,omitempty"`
+
+	Style     string    `yaml:"style"`
+	AI        AI        `yaml:"ai"`
+	Rules     Rules     `yaml:"rules"`
+	Hook      Hook      `yaml:"hook"`
+	Branch    Branch    `yaml:"branch"`
+	Release   Release   `yaml:"release"`
+	Changelog Changelog `yaml:"changelog"`
 }
 
 var (
@@ -43,11 +135,12 @@ var (
 	ErrConfigMalformed = errors.New("config file is malformed")
 )
 
-// Default returns a Config struct populated with sensible defaults.
-// These must always mirror what `bartle init` generates.
+// Default returns the built-in defaults, which apply to every key a config
+// file leaves out. `bartle init` fills its templates from these values.
 func Default() Config {
 	return Config{
-		Style: "conventional",
+		Version: CurrentVersion,
+		Style:   "conventional",
 		AI: AI{
 			Enabled:     false,
 			Provider:    "openai",
@@ -56,26 +149,48 @@ func Default() Config {
 			APIKey:      "env:OPENAI_API_KEY",
 		},
 		Rules: Rules{
-			ScopeRequired:  true,
-			MaxLineLength:  72,
-			LowercaseStart: false,
-			Types:          []string{"feat", "fix", "docs", "refactor", "test", "chore"},
+			ScopeRequired:     true,
+			MaxLineLength:     72,
+			BodyMaxLineLength: 100,
+			LowercaseStart:    false,
+			Types:             []string{"feat", "fix", "docs", "refactor", "test", "chore"},
+
+			BreakingFooterRequired: false,
+			BreakingMarkerRequired: false,
 		},
 		Hook: Hook{
 			AutoApply:   false,
 			BlockOnFail: true,
 		},
+		Branch: Branch{
+			Ignore: []string{"main", "master"},
+			Extract: []string{
+				`^(?P<type>[a-z]+)/`,
+				`(?P<ticket>[A-Z]{2,}-[0-9]+)`,
+			},
+		},
+		Release: Release{
+			TagPrefix: "v",
+			Bumps:     map[string]string{"feat": "minor", "fix": "patch"},
+		},
+		Changelog: Changelog{
+			Sections: []ChangelogSection{
+				{Title: "Features", Types: []string{"feat"}},
+				{Title: "Bug Fixes", Types: []string{"fix"}},
+				{Title: "Refactoring", Types: []string{"refactor"}},
+			},
+		},
 	}
 }
 
-// findRepoConfigPath walks upward from the starting directory
-// until it finds a `.git` folder, then returns the path to `.bartle.yaml`
-// within that repo root.
+// findRepoConfigPath walks upward from the starting directory until it
+// finds a `.git` folder, then returns the config file in that repo root (see
+// FileNames), or the path to `.bartle.yaml` when there is none.
 func findRepoConfigPath(startDir string) (string, error) {
 	currentDir := startDir
 	for {
 		if _, err := os.Stat(filepath.Join(currentDir, ".git")); err == nil {
-			return filepath.Join(currentDir, ".bartle.yaml"), nil
+			return preferredConfig(currentDir), nil
 		}
 		parentDir := filepath.Dir(currentDir)
 		if parentDir == currentDir {
@@ -85,39 +200,212 @@ func findRepoConfigPath(startDir string) (string, error) {
 	}
 }
 
-// Load merges defaults with YAML config from the repo root.
-// Returns the loaded config, the path to the config file, and any error.
+// RepoConfigPath returns the repo's config file as found from the working
+// directory (see FileNames). The file may not exist.
+func RepoConfigPath() (string, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("get working directory: %w", err)
+	}
+	return findRepoConfigPath(workingDir)
+}
+
+// Load merges, in increasing order of precedence, the defaults, the user's
+// global config, the repo config and the environment and --set overrides.
+// A --config file (see SetFlags) replaces both config files. Returns the
+// loaded config, the path to the config file used, and any error.
+// ErrConfigNotFound means neither a repo nor a global config exists; the
+// defaults are returned with it.
 func Load() (Config, string, error) {
-	defaultConfig := Default()
+	return LoadFor(nil)
+}
+
+// LoadFor is Load plus the nested config files in the directories
+// containing paths (relative to the repo root), e.g. the files a commit
+// touches. Nested configs are applied after the repo config, shallower
+// directories first. Configs at the same depth apply in path order, so for a
+// commit touching svc/api and svc/web, svc/web's values win where both set a
+// key.
+func LoadFor(paths []string) (Config, string, error) {
+	r, err := Inspect(paths)
+	if err == nil && len(r.Problems) > 0 {
+		err = fmt.Errorf("%w: %v", ErrConfigMalformed, r.Problems[0])
+	}
+	return r.Config, r.Path, err
+}
+
+// Report is a loaded config together with where each value came from and
+// everything wrong with it.
+type Report struct {
+	Config   Config
+	Sources  Sources
+	Path     string // the repo config, which may not exist
+	Problems []Problem
+}
+
+// Inspect loads the config like LoadFor, but instead of stopping at the
+// first problem it skips what it can't use and reports every problem. The
+// error is only for failing to find the config at all (ErrNotInGitRepo,
+// ErrConfigNotFound, or a --config file that doesn't exist).
+//
+// Environment and --set overrides (see EnvPrefix and SetFlags) apply on top
+// of the files.
+func Inspect(paths []string) (Report, error) {
+	l := newLoader("")
+	report := func(path string) Report {
+		return Report{Config: l.cfg, Sources: l.sources, Path: path, Problems: l.problems}
+	}
 
 	workingDir, err := os.Getwd()
 	if err != nil {
-		return defaultConfig, "", fmt.Errorf("get working directory: %w", err)
+		return report(""), fmt.Errorf("get working directory: %w", err)
 	}
 
 	configPath, err := findRepoConfigPath(workingDir)
-	if err != nil {
-		return defaultConfig, "", err
+	var files []string
+	switch {
+	case flags.File != "":
+		// An explicit file works outside a repository too; vendored
+		// extends are then relative to its directory.
+		l.root = filepath.Dir(configPath)
+		configPath = flags.File
+		if !filepath.IsAbs(configPath) {
+			configPath = filepath.Join(workingDir, configPath)
+		}
+		if !fileExists(configPath) {
+			return report(configPath), fmt.Errorf("config file %s does not exist", flags.File)
+		}
+		if err != nil {
+			l.root = filepath.Dir(configPath)
+		}
+		files = []string{configPath}
+	case err != nil:
+		return report(""), err
+	default:
+		l.root = filepath.Dir(configPath)
+		if global, ok := GlobalPath(); ok && fileExists(global) {
+			files = append(files, global)
+		}
+		if fileExists(configPath) {
+			files = append(files, configPath)
+		}
+		if len(files) == 0 {
+			l.applyOverrides()
+			return report(configPath), ErrConfigNotFound
+		}
+		files = append(files, nestedConfigs(l.root, paths)...)
 	}
 
-	rawBytes, err := os.ReadFile(configPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return defaultConfig, configPath, ErrConfigNotFound
+	for _, f := range files {
+		l.checkAmbiguous(f)
+		l.applyFile(f)
+	}
+	l.applyOverrides()
+	for _, p := range validate(l.cfg) {
+		l.problems = append(l.problems, l.sources.Locate(p))
+	}
+
+	return report(configPath), nil
+}
+
+// normalize removes a UTF-8 BOM and CRLF line endings.
+func normalize(raw []byte) []byte {
+	raw = bytes.TrimPrefix(raw, []byte{0xEF, 0xBB, 0xBF}) // UTF-8 BOM
+	return bytes.ReplaceAll(raw, []byte("\r\n"), []byte("\n"))
+}
+
+// Problem is something wrong with the config: a file that doesn't parse, an
+// unknown key, or a value that can never work.
+type Problem struct {
+	Origin  Origin // where it was set, when known
+	Field   string // dotted path, e.g. rules.pattern or branch.patterns[1]
+	Message string
+}
+
+func (p Problem) Error() string {
+	var b strings.Builder
+	if p.Origin.File != "" {
+		b.WriteString(p.Origin.String() + ": ")
+	}
+	if p.Field != "" {
+		b.WriteString(p.Field + ": ")
+	}
+	b.WriteString(p.Message)
+	return b.String()
+}
+
+// yamlProblems turns a yaml.v3 error into one problem per message, picking
+// out the line numbers yaml.v3 embeds in them.
+func yamlProblems(file string, err error) []Problem {
+	var te *yaml.TypeError
+	if errors.As(err, &te) {
+		out := make([]Problem, 0, len(te.Errors))
+		for _, msg := range te.Errors {
+			out = append(out, lineProblem(file, msg))
 		}
-		return defaultConfig, configPath, fmt.Errorf("read config: %w", err)
+		return out
 	}
+	return []Problem{lineProblem(file, strings.TrimPrefix(err.Error(), "yaml: "))}
+}
 
-	// Normalize content (remove BOM and CRLF)
-	rawBytes = bytes.TrimPrefix(rawBytes, []byte{0xEF, 0xBB, 0xBF}) // UTF-8 BOM
-	rawBytes = bytes.ReplaceAll(rawBytes, []byte("\r\n"), []byte("\n"))
+func lineProblem(file, msg string) Problem {
+	p := Problem{Origin: Origin{File: file}, Message: msg}
+	if rest, ok := strings.CutPrefix(msg, "line "); ok {
+		if num, text, ok := strings.Cut(rest, ": "); ok {
+			if n, err := strconv.Atoi(num); err == nil {
+				p.Origin.Line, p.Message = n, text
+			}
+		}
+	}
+	return p
+}
 
-	decoder := yaml.NewDecoder(bytes.NewReader(rawBytes))
-	decoder.KnownFields(true) // catch typos and unknown fields
+// validate catches values that decode fine but can never work. Problems are
+// sorted by field so the output is stable.
+func validate(cfg Config) []Problem {
+	var out []Problem
+	add := func(field, format string, args ...any) {
+		out = append(out, Problem{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
 
-	if err := decoder.Decode(&defaultConfig); err != nil {
-		return defaultConfig, configPath, fmt.Errorf("%w: %v", ErrConfigMalformed, err)
+	if cfg.Rules.Pattern != "" {
+		if _, err := regexp.Compile(cfg.Rules.Pattern); err != nil {
+			add("rules.pattern", "not a valid regular expression: %v", err)
+		}
+	}
+	for id, sev := range cfg.Rules.Severity {
+		switch strings.ToLower(sev) {
+		case "error", "warn", "off":
+		default:
+			add("rules.severity."+id, "invalid severity %q (allowed: error|warn|off)", sev)
+		}
+	}
+	for i, p := range cfg.Branch.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			add(fmt.Sprintf("branch.patterns[%d]", i), "not a valid regular expression: %v", err)
+		}
+	}
+	for i, p := range cfg.Branch.Extract {
+		if _, err := regexp.Compile(p); err != nil {
+			add(fmt.Sprintf("branch.extract[%d]", i), "not a valid regular expression: %v", err)
+		}
+	}
+	for typ, bump := range cfg.Release.Bumps {
+		switch strings.ToLower(bump) {
+		case "major", "minor", "patch", "none":
+		default:
+			add("release.bumps."+typ, "invalid bump %q (allowed: major|minor|patch|none)", bump)
+		}
+	}
+	for i, sec := range cfg.Changelog.Sections {
+		if strings.TrimSpace(sec.Title) == "" || len(sec.Types) == 0 {
+			add(fmt.Sprintf("changelog.sections[%d]", i), "title and types are required")
+		}
+	}
+	if strings.EqualFold(cfg.Style, "custom") && cfg.Rules.Pattern == "" {
+		add("style", "style \"custom\" requires rules.pattern")
 	}
 
-	return defaultConfig, configPath, nil
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out
 }
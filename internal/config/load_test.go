@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInspectReportsEveryProblem(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(root)
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, FileName), `extends: ./missing.yaml
+rules:
+  max_len: 10
+  max_line_length: 50
+  severity:
+    subject-case: loud
+branch:
+  patterns: ['^ok$', '(']
+`)
+
+	r, err := Inspect(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		`.bartle.yaml: extends "./missing.yaml"`,
+		".bartle.yaml:3: rules.max_len: unknown key",
+		".bartle.yaml:8: branch.patterns[1]: not a valid regular expression",
+		`.bartle.yaml:6: rules.severity.subject-case: invalid severity "loud"`,
+	}
+	if len(r.Problems) != len(want) {
+		t.Fatalf("got %d problems, want %d: %v", len(r.Problems), len(want), r.Problems)
+	}
+	for i, p := range r.Problems {
+		if got := p.Error(); !strings.HasPrefix(got, want[i]) {
+			t.Errorf("problem %d = %q, want prefix %q", i, got, want[i])
+		}
+	}
+	if r.Config.Rules.MaxLineLength != 50 {
+		t.Errorf("max_line_length = %d, valid keys should still apply", r.Config.Rules.MaxLineLength)
+	}
+}
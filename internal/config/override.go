@@ -0,0 +1,240 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the environment variables that override config values:
+// BARTLE_RULES_MAX_LINE_LENGTH sets rules.max_line_length. For maps the rest
+// of the name is the key, lowercase with dashes:
+// BARTLE_RULES_SEVERITY_SUBJECT_CASE sets rules.severity.subject-case.
+const EnvPrefix = "BARTLE_"
+
+// Flags are the command-line settings that apply to every config load.
+type Flags struct {
+	// File replaces the global, repository and nested configs.
+	File string
+	// Set holds key=value overrides, e.g. rules.scope_required=false. They
+	// apply after the environment.
+	Set []string
+}
+
+var flags Flags
+
+// SetFlags makes every later Load use f.
+func SetFlags(f Flags) { flags = f }
+
+// override is one value to set on top of the config files.
+type override struct {
+	key, value string
+	origin     Origin
+}
+
+// overrides collects the environment overrides, then the --set ones.
+// Malformed --set entries are returned as problems.
+func overrides(environ []string, set []string) ([]override, []Problem) {
+	var out []override
+	var problems []Problem
+
+	leaves, maps := overridableKeys()
+	sort.Strings(environ)
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		rest, ok := strings.CutPrefix(name, EnvPrefix)
+		if !ok {
+			continue
+		}
+		origin := Origin{File: "$" + name}
+		if key, ok := leaves[rest]; ok {
+			out = append(out, override{key: key, value: value, origin: origin})
+			continue
+		}
+		for env, key := range maps {
+			if entry, ok := strings.CutPrefix(rest, env+"_"); ok && entry != "" {
+				entry = strings.ReplaceAll(strings.ToLower(entry), "_", "-")
+				out = append(out, override{key: key + "." + entry, value: value, origin: origin})
+				break
+			}
+		}
+		// Anything else isn't ours to judge, e.g. BARTLE_TOKEN for a script.
+	}
+
+	for _, s := range set {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			problems = append(problems, Problem{Origin: Origin{File: "--set"}, Message: fmt.Sprintf("%q is not key=value", s)})
+			continue
+		}
+		out = append(out, override{key: strings.TrimSpace(key), value: value, origin: Origin{File: "--set"}})
+	}
+	return out, problems
+}
+
+// overridableKeys maps environment names (without EnvPrefix) to the dotted
+// keys they set: leaves for single values, maps for keys whose entries are
+// set one by one.
+func overridableKeys() (leaves, maps map[string]string) {
+	leaves, maps = map[string]string{}, map[string]string{}
+	var walk func(t reflect.Type, key string)
+	walk = func(t reflect.Type, key string) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if name == "" || name == "-" || notOverridable[name] && key == "" {
+				continue
+			}
+			child := name
+			if key != "" {
+				child = key + "." + name
+			}
+			env := strings.ToUpper(strings.ReplaceAll(child, ".", "_"))
+			switch f.Type.Kind() {
+			case reflect.Struct:
+				walk(f.Type, child)
+			case reflect.Map:
+				maps[env] = child
+				leaves[env] = child
+			default:
+				leaves[env] = child
+			}
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "")
+	return leaves, maps
+}
+
+// notOverridable are top-level keys that only make sense in a file.
+var notOverridable = map[string]bool{"version": true, "extends": true}
+
+// setValue sets the dotted key on cfg from its string form, checking it
+// against the field's type.
+func setValue(cfg *Config, key, value string) error {
+	parts := strings.Split(key, ".")
+	if notOverridable[parts[0]] {
+		return fmt.Errorf("can only be set in a config file")
+	}
+
+	v := reflect.ValueOf(cfg).Elem()
+	for i, part := range parts {
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := fieldByTag(v, part)
+			if !ok {
+				return fmt.Errorf("unknown key")
+			}
+			v = f
+		case reflect.Map:
+			if i != len(parts)-1 {
+				return fmt.Errorf("unknown key")
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := parseValue(elem, value); err != nil {
+				return err
+			}
+			if v.IsNil() {
+				v.Set(reflect.MakeMap(v.Type()))
+			}
+			v.SetMapIndex(reflect.ValueOf(part), elem)
+			return nil
+		default:
+			return fmt.Errorf("unknown key")
+		}
+	}
+	if v.Kind() == reflect.Struct {
+		return fmt.Errorf("is a section; set one of its keys")
+	}
+	return parseValue(v, value)
+}
+
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// parseValue converts s to v's type. Lists of strings may be written
+// comma-separated; anything more complex takes YAML flow syntax, e.g.
+// [{title: Features, types: [feat]}].
+func parseValue(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", s)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", s)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		trimmed := strings.TrimSpace(s)
+		if v.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(trimmed, "[") {
+			var list []string
+			for _, item := range strings.Split(s, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			v.Set(reflect.ValueOf(list))
+			return nil
+		}
+		return parseYAML(v, trimmed)
+	default:
+		return parseYAML(v, strings.TrimSpace(s))
+	}
+	return nil
+}
+
+func parseYAML(v reflect.Value, s string) error {
+	target := reflect.New(v.Type())
+	dec := yaml.NewDecoder(strings.NewReader(s))
+	dec.KnownFields(true)
+	if err := dec.Decode(target.Interface()); err != nil {
+		return fmt.Errorf("%q is not a valid %s: %v", s, v.Type(), strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	v.Set(target.Elem())
+	return nil
+}
+
+// applyOverrides sets the environment and --set overrides on top of the
+// loaded files.
+func (l *loader) applyOverrides() {
+	list, problems := overrides(os.Environ(), flags.Set)
+	l.problems = append(l.problems, problems...)
+	for _, o := range list {
+		if err := setValue(&l.cfg, o.key, o.value); err != nil {
+			l.problems = append(l.problems, Problem{Origin: o.origin, Field: o.key, Message: err.Error()})
+			continue
+		}
+		// The value replaces whatever the files set, lists and maps included.
+		for k := range l.sources {
+			if strings.HasPrefix(k, o.key+".") || strings.HasPrefix(k, o.key+"[") {
+				delete(l.sources, k)
+			}
+		}
+		l.sources[o.key] = o.origin
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetValue(t *testing.T) {
+	tests := []struct {
+		key, value string
+		check      func(c Config) any
+		want       any
+		wantErr    string
+	}{
+		{key: "rules.max_line_length", value: "100", check: func(c Config) any { return c.Rules.MaxLineLength }, want: 100},
+		{key: "rules.scope_required", value: "false", check: func(c Config) any { return c.Rules.ScopeRequired }, want: false},
+		{key: "ai.temperature", value: "0.5", check: func(c Config) any { return c.AI.Temperature }, want: 0.5},
+		{key: "rules.pattern", value: "^a: b #c$", check: func(c Config) any { return c.Rules.Pattern }, want: "^a: b #c$"},
+		{key: "rules.types", value: "feat, fix", check: func(c Config) any { return c.Rules.Types }, want: []string{"feat", "fix"}},
+		{key: "rules.types", value: "[feat, 'a,b']", check: func(c Config) any { return c.Rules.Types }, want: []string{"feat", "a,b"}},
+		{key: "rules.severity.subject-case", value: "warn", check: func(c Config) any { return c.Rules.Severity["subject-case"] }, want: "warn"},
+		{key: "release.bumps", value: "{docs: patch}", check: func(c Config) any { return c.Release.Bumps }, want: map[string]string{"docs": "patch"}},
+		{
+			key: "changelog.sections", value: "[{title: Fixes, types: [fix]}]",
+			check: func(c Config) any { return c.Changelog.Sections },
+			want:  []ChangelogSection{{Title: "Fixes", Types: []string{"fix"}}},
+		},
+		{key: "rules.max_line_length", value: "long", wantErr: `"long" is not a whole number`},
+		{key: "hook.block_on_fail", value: "maybe", wantErr: `"maybe" is not true or false`},
+		{key: "rules.max_len", value: "1", wantErr: "unknown key"},
+		{key: "rules.max_line_length.x", value: "1", wantErr: "unknown key"},
+		{key: "rules", value: "1", wantErr: "is a section; set one of its keys"},
+		{key: "extends", value: "bartle:strict", wantErr: "can only be set in a config file"},
+		{key: "changelog.sections", value: "[{name: x}]", wantErr: `"[{name: x}]" is not a valid []config.ChangelogSection`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cfg := Default()
+			err := setValue(&cfg, tt.key, tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := tt.check(cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvOverrideNames(t *testing.T) {
+	got, problems := overrides([]string{
+		"BARTLE_RULES_MAX_LINE_LENGTH=100",
+		"BARTLE_RULES_SEVERITY_SUBJECT_CASE=off",
+		"BARTLE_RELEASE_BUMPS_FEAT=patch",
+		"BARTLE_TOKEN=unrelated",
+		"BARTLE_VERSION=3",
+		"PATH=/bin",
+	}, []string{"rules.types=feat", "novalue"})
+
+	want := []override{
+		{key: "release.bumps.feat", value: "patch", origin: Origin{File: "$BARTLE_RELEASE_BUMPS_FEAT"}},
+		{key: "rules.max_line_length", value: "100", origin: Origin{File: "$BARTLE_RULES_MAX_LINE_LENGTH"}},
+		{key: "rules.severity.subject-case", value: "off", origin: Origin{File: "$BARTLE_RULES_SEVERITY_SUBJECT_CASE"}},
+		{key: "rules.types", value: "feat", origin: Origin{File: "--set"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("overrides mismatch\nwant: %+v\ngot:  %+v", want, got)
+	}
+	if len(problems) != 1 || problems[0].Error() != `--set: "novalue" is not key=value` {
+		t.Fatalf("problems = %v", problems)
+	}
+}
+
+func TestInspectOverrides(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(root)
+	t.Cleanup(func() { SetFlags(Flags{}) })
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, FileName), "rules:\n  max_line_length: 60\n  scope_required: true\n")
+	writeFile(t, filepath.Join(root, "ci.yaml"), "style: jira\n")
+
+	t.Setenv("BARTLE_RULES_MAX_LINE_LENGTH", "80")
+	t.Setenv("BARTLE_RULES_SCOPE_REQUIRED", "false")
+	SetFlags(Flags{Set: []string{"rules.max_line_length=90"}})
+
+	r, err := Inspect(nil)
+	if err != nil || len(r.Problems) > 0 {
+		t.Fatalf("unexpected error: %v %v", err, r.Problems)
+	}
+	if r.Config.Rules.MaxLineLength != 90 || r.Config.Rules.ScopeRequired {
+		t.Fatalf("max_line_length=%d scope_required=%v, want --set over env over file",
+			r.Config.Rules.MaxLineLength, r.Config.Rules.ScopeRequired)
+	}
+	if o := r.Sources["rules.max_line_length"]; o.File != "--set" {
+		t.Fatalf("rules.max_line_length from %v", o)
+	}
+
+	SetFlags(Flags{File: "ci.yaml"})
+	r, err = Inspect(nil)
+	if err != nil || len(r.Problems) > 0 {
+		t.Fatalf("--config: unexpected error: %v %v", err, r.Problems)
+	}
+	if r.Config.Style != "jira" || r.Config.Rules.MaxLineLength != 80 {
+		t.Fatalf("--config: style=%s max_line_length=%d, want jira and the repo file ignored",
+			r.Config.Style, r.Config.Rules.MaxLineLength)
+	}
+
+	SetFlags(Flags{File: "missing.yaml"})
+	if _, err := Inspect(nil); err == nil {
+		t.Fatal("--config with a missing file should fail")
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// descriptions feeds editor tooltips, keyed like Sources.
+var descriptions = map[string]string{
+	"version":                        "Config schema version; `bartle config migrate` updates older files.",
+	"extends":                        "Base configs applied before this file: bartle:<preset>, ./relative/path.yaml or a path under vendor/.",
+	"style":                          "Commit message style.",
+	"ai":                             "AI-assisted suggestions.",
+	"ai.api_key":                     "API key, or env:VAR to read it from the environment.",
+	"rules":                          "Commit message rules.",
+	"rules.scope_required":           "Require a scope, e.g. feat(ui): ...",
+	"rules.max_line_length":          "Maximum header length.",
+	"rules.body_max_line_length":     "Maximum length of body lines.",
+	"rules.lowercase_start":          "Require the subject to start lowercase.",
+	"rules.types":                    "Allowed commit types.",
+	"rules.pattern":                  "Regular expression the header must match; named groups feed the custom style.",
+	"rules.severity":                 "Severity overrides by rule ID.",
+	"rules.breaking_footer_required": "Require a BREAKING CHANGE footer when the header has '!'.",
+	"rules.breaking_marker_required": "Require '!' in the header when there is a BREAKING CHANGE footer.",
+	"hook":                           "What the commit-msg hook does.",
+	"hook.auto_apply":                "Apply automatic fixes to the message before validating it.",
+	"hook.block_on_fail":             "Reject commits that fail lint; false only reports problems.",
+	"branch":                         "Branch naming rules and what the prepare-commit-msg hook extracts from branch names.",
+	"branch.prefixes":                "Allowed branch name prefixes, e.g. feat/.",
+	"branch.patterns":                "Regular expressions a branch name may match instead of a prefix.",
+	"branch.ignore":                  "Branch names exempt from the rules.",
+	"branch.extract":                 "Regular expressions whose named groups (ticket, type, scope) pre-fill commit messages.",
+	"release":                        "How bartle next-version bumps versions.",
+	"release.tag_prefix":             "Prefix stripped from tags before parsing them as versions.",
+	"release.bumps":                  "Version bump by commit type; breaking changes always bump major.",
+	"changelog":                      "How bartle changelog groups commits.",
+	"changelog.sections":             "Sections in order; types not listed are left out.",
+}
+
+// Schema returns a JSON Schema for the config file, generated from the yaml
+// tags on Config. styles and rules are the names the linter knows, offered
+// as completions for style and the keys of rules.severity.
+func Schema(styles, rules []string) ([]byte, error) {
+	s := schemaFor(reflect.TypeOf(Config{}), "")
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["title"] = "bartle configuration"
+
+	props := s["properties"].(map[string]any)
+	props["style"].(map[string]any)["enum"] = styles
+	props["version"].(map[string]any)["minimum"] = 1
+	props["version"].(map[string]any)["maximum"] = CurrentVersion
+
+	ruleProps := props["rules"].(map[string]any)["properties"].(map[string]any)
+	severity := ruleProps["severity"].(map[string]any)
+	severity["propertyNames"] = map[string]any{"enum": rules}
+	severity["additionalProperties"] = map[string]any{"enum": []string{"error", "warn", "off"}}
+
+	releaseProps := props["release"].(map[string]any)["properties"].(map[string]any)
+	releaseProps["bumps"].(map[string]any)["additionalProperties"] = map[string]any{
+		"enum": []string{"major", "minor", "patch", "none"},
+	}
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+var stringListType = reflect.TypeOf(StringList{})
+
+func schemaFor(t reflect.Type, key string) map[string]any {
+	s := map[string]any{}
+	if d, ok := descriptions[key]; ok {
+		s["description"] = d
+	}
+
+	if t == stringListType {
+		s["oneOf"] = []any{
+			map[string]any{"type": "string"},
+			map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		}
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		props := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			child := name
+			if key != "" {
+				child = key + "." + name
+			}
+			props[name] = schemaFor(f.Type, child)
+		}
+		s["type"] = "object"
+		s["properties"] = props
+		s["additionalProperties"] = false
+	case reflect.Map:
+		s["type"] = "object"
+		s["additionalProperties"] = schemaFor(t.Elem(), "")
+	case reflect.Slice:
+		s["type"] = "array"
+		s["items"] = schemaFor(t.Elem(), "")
+	case reflect.String:
+		s["type"] = "string"
+	case reflect.Bool:
+		s["type"] = "boolean"
+	case reflect.Int, reflect.Int64:
+		s["type"] = "integer"
+	case reflect.Float64:
+		s["type"] = "number"
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	raw, err := Schema([]string{"conventional"}, []string{"subject-case"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s struct {
+		AdditionalProperties bool `json:"additionalProperties"`
+		Properties           map[string]struct {
+			Type       string                     `json:"type"`
+			Enum       []string                   `json:"enum"`
+			OneOf      []map[string]any           `json:"oneOf"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	if s.AdditionalProperties {
+		t.Error("unknown top-level keys should be rejected")
+	}
+	for _, key := range []string{"extends", "style", "ai", "rules", "hook", "branch", "release", "changelog"} {
+		if _, ok := s.Properties[key]; !ok {
+			t.Errorf("schema lacks %s", key)
+		}
+	}
+	if got := s.Properties["style"].Enum; len(got) != 1 || got[0] != "conventional" {
+		t.Errorf("style enum = %v", got)
+	}
+	if len(s.Properties["extends"].OneOf) != 2 {
+		t.Errorf("extends should accept a string or a list")
+	}
+	if _, ok := s.Properties["rules"].Properties["max_line_length"]; !ok {
+		t.Errorf("rules lacks max_line_length")
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Annotate renders cfg as YAML with a comment after each value naming the
+// file and line that set it, or "default" for values nobody set.
+func Annotate(cfg Config, src Sources) ([]byte, error) {
+	var doc yaml.Node
+	if err := doc.Encode(cfg); err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	annotate(&doc, "", src)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// annotate comments the leaves of the mapping n. Lists are replaced as a
+// whole, so they count as leaves too.
+func annotate(n *yaml.Node, key string, src Sources) {
+	if n.Kind == yaml.DocumentNode {
+		for _, c := range n.Content {
+			annotate(c, key, src)
+		}
+		return
+	}
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		child := k.Value
+		if key != "" {
+			child = key + "." + k.Value
+		}
+		if v.Kind == yaml.MappingNode && len(v.Content) > 0 {
+			annotate(v, child, src)
+			continue
+		}
+
+		comment := "default"
+		if o, ok := src[child]; ok {
+			comment = o.String()
+		}
+		if v.Kind == yaml.ScalarNode || len(v.Content) == 0 {
+			v.LineComment = comment
+		} else {
+			k.LineComment = comment
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotate(t *testing.T) {
+	cfg := Default()
+	cfg.Rules.MaxLineLength = 100
+	cfg.Rules.Severity = map[string]string{"subject-case": "off"}
+	src := Sources{
+		"rules.max_line_length":       {File: ".bartle.yaml", Line: 3},
+		"rules.severity":              {File: "bartle:strict", Line: 4},
+		"rules.severity.subject-case": {File: "bartle:strict", Line: 5},
+		"branch.ignore":               {File: "svc/.bartle.yaml", Line: 2},
+	}
+
+	out, err := Annotate(cfg, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"style: conventional # default\n",
+		"  max_line_length: 100 # .bartle.yaml:3\n",
+		`    subject-case: "off" # bartle:strict:5` + "\n",
+		"  ignore: # svc/.bartle.yaml:2\n    - main\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
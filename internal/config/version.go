@@ -0,0 +1,128 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config schema this bartle writes and reads. Files
+// without a version key are version 1.
+const CurrentVersion = 1
+
+// migration upgrades a file's root mapping by one version.
+type migration func(root *yaml.Node) error
+
+// migrations upgrade a file from the version they are keyed by to the next
+// one. Bumping CurrentVersion means adding an entry here; the loader applies
+// them in memory to older files before decoding, and `bartle config migrate`
+// rewrites the files.
+var migrations = map[int]migration{}
+
+// ErrNewerVersion means a config was written for a newer bartle.
+var ErrNewerVersion = errors.New("config version is newer than this bartle supports")
+
+// fileVersion reads the version key of a root mapping, 1 when it is absent,
+// and rejects versions above latest. line is where the key is, 0 when absent.
+func fileVersion(root *yaml.Node, latest int) (version, line int, err error) {
+	_, v := mappingEntry(root, "version")
+	if v == nil {
+		return 1, 0, nil
+	}
+	n, err := strconv.Atoi(v.Value)
+	if err != nil || v.Kind != yaml.ScalarNode || n < 1 {
+		return 0, v.Line, fmt.Errorf("version must be a whole number from 1 to %d", latest)
+	}
+	if n > latest {
+		return n, v.Line, fmt.Errorf("%w (version %d, supported up to %d); upgrade bartle", ErrNewerVersion, n, latest)
+	}
+	return n, v.Line, nil
+}
+
+// mappingEntry finds key in a mapping node.
+func mappingEntry(m *yaml.Node, key string) (k, v *yaml.Node) {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i], m.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// Migrate rewrites a config file to CurrentVersion. from is the version the
+// file had; when it is already current raw is returned unchanged.
+//
+// A file that needs migrating is re-encoded from its parsed form: comments
+// and key order survive, but blank lines are dropped and comment spacing is
+// normalised.
+func Migrate(raw []byte) (out []byte, from int, err error) {
+	return migrate(raw, CurrentVersion, migrations)
+}
+
+func migrate(raw []byte, to int, steps map[int]migration) (out []byte, from int, err error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(normalize(raw), &doc); err != nil {
+		return nil, 0, err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, 0, fmt.Errorf("line %d: the config must be a mapping of keys", root.Line)
+	}
+
+	from, line, err := fileVersion(root, to)
+	if err != nil {
+		return nil, from, fmt.Errorf("line %d: %w", line, err)
+	}
+	if from == to {
+		return raw, from, nil
+	}
+	if err := upgrade(root, from, to, steps); err != nil {
+		return nil, from, err
+	}
+	setVersion(root, to)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, from, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, from, err
+	}
+	return buf.Bytes(), from, nil
+}
+
+// upgrade applies steps to root in memory, from version from up to to.
+func upgrade(root *yaml.Node, from, to int, steps map[int]migration) error {
+	for v := from; v < to; v++ {
+		if err := steps[v](root); err != nil {
+			return fmt.Errorf("migrate from version %d: %w", v, err)
+		}
+	}
+	return nil
+}
+
+// setVersion updates the version key, or adds it as the first key.
+func setVersion(root *yaml.Node, version int) {
+	if _, v := mappingEntry(root, "version"); v != nil {
+		v.Value = strconv.Itoa(version)
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	if len(root.Content) > 0 {
+		// Keep a comment heading the file at the top.
+		key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
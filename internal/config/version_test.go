@@ -0,0 +1,153 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		want     string
+		wantFrom int
+		wantErr  error
+	}{
+		{
+			name:     "unversioned is current and untouched",
+			in:       "# Team rules\nstyle:   jira\n\nrules:\n  max_line_length: 60\n",
+			want:     "# Team rules\nstyle:   jira\n\nrules:\n  max_line_length: 60\n",
+			wantFrom: 1,
+		},
+		{
+			name:     "explicit version 1",
+			in:       "version: 1\nstyle: jira\n",
+			want:     "version: 1\nstyle: jira\n",
+			wantFrom: 1,
+		},
+		{
+			name:    "newer",
+			in:      "version: 2\n",
+			wantErr: ErrNewerVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, from, err := Migrate([]byte(tt.in))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if from != tt.wantFrom {
+				t.Errorf("from = %d, want %d", from, tt.wantFrom)
+			}
+			if string(out) != tt.want {
+				t.Errorf("output mismatch\nwant: %q\ngot:  %q", tt.want, out)
+			}
+		})
+	}
+}
+
+// There are no migrations yet, so the tests below use a made-up one that
+// renames rules.max_len to rules.max_line_length in version 2.
+var renameMaxLen = map[int]migration{
+	1: func(root *yaml.Node) error {
+		_, rules := mappingEntry(root, "rules")
+		if k, _ := mappingEntry(rules, "max_len"); k != nil {
+			k.Value = "max_line_length"
+		}
+		return nil
+	},
+}
+
+func TestMigrateSteps(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		in       string
+		want     string
+		wantFrom int
+	}{
+		{
+			name:     "unversioned keeps comments and order",
+			in:       "# Team rules\nstyle: jira # ABC-123: subject\nrules:\n  # shorter than the default\n  max_len: 60\n",
+			want:     "# Team rules\nversion: 2\nstyle: jira # ABC-123: subject\nrules:\n  # shorter than the default\n  max_line_length: 60\n",
+			wantFrom: 1,
+		},
+		{
+			name:     "explicit version 1",
+			in:       "rules:\n  max_len: 60\nversion: 1\n",
+			want:     "rules:\n  max_line_length: 60\nversion: 2\n",
+			wantFrom: 1,
+		},
+		{
+			name:     "current is untouched",
+			in:       "version: 2\nstyle:   jira\n",
+			want:     "version: 2\nstyle:   jira\n",
+			wantFrom: 2,
+		},
+		{
+			name:     "empty file",
+			in:       "",
+			want:     "version: 2\n",
+			wantFrom: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, from, err := migrate([]byte(tt.in), 2, renameMaxLen)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if from != tt.wantFrom {
+				t.Errorf("from = %d, want %d", from, tt.wantFrom)
+			}
+			if string(out) != tt.want {
+				t.Errorf("output mismatch\nwant: %q\ngot:  %q", tt.want, out)
+			}
+		})
+	}
+}
+
+// The loader upgrades older files in memory, so they load unmigrated.
+func TestLoaderMigratesOlderFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		maxLen   int
+		line     int // where rules.max_line_length was set
+		problems int
+	}{
+		{name: "unversioned", in: "rules:\n  max_len: 60\n", maxLen: 60, line: 2},
+		{name: "version 1", in: "version: 1\nrules:\n  max_len: 60\n", maxLen: 60, line: 3},
+		{name: "current", in: "version: 2\nrules:\n  max_line_length: 60\n", maxLen: 60, line: 3},
+		{name: "old key at the current version", in: "version: 2\nrules:\n  max_len: 60\n", maxLen: 72, problems: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLoader("")
+			l.version, l.migrations = 2, renameMaxLen
+			l.apply(FileName, []byte(tt.in))
+			if len(l.problems) != tt.problems {
+				t.Fatalf("problems = %v, want %d", l.problems, tt.problems)
+			}
+			if l.cfg.Rules.MaxLineLength != tt.maxLen {
+				t.Fatalf("max_line_length = %d, want %d", l.cfg.Rules.MaxLineLength, tt.maxLen)
+			}
+			if tt.line != 0 {
+				if o := l.sources["rules.max_line_length"]; o.Line != tt.line {
+					t.Fatalf("rules.max_line_length from %v, want line %d", o, tt.line)
+				}
+			}
+		})
+	}
+}
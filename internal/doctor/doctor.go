@@ -0,0 +1,189 @@
+// Package doctor checks that bartle is set up so the hooks actually run.
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/hooks"
+	"github.com/RyanTalbot/bartle/internal/lint"
+)
+
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+)
+
+// Check is the outcome of one diagnostic. Hint says how to fix anything
+// that didn't pass.
+type Check struct {
+	Name    string
+	Status  Status
+	Message string
+	Hint    string
+}
+
+func pass(name, format string, args ...any) Check {
+	return Check{Name: name, Status: Pass, Message: fmt.Sprintf(format, args...)}
+}
+
+func warn(name, hint, format string, args ...any) Check {
+	return Check{Name: name, Status: Warn, Message: fmt.Sprintf(format, args...), Hint: hint}
+}
+
+func fail(name, hint, format string, args ...any) Check {
+	return Check{Name: name, Status: Fail, Message: fmt.Sprintf(format, args...), Hint: hint}
+}
+
+// Failed reports whether any check failed.
+func Failed(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+// Run performs every check from the working directory. Checks that depend
+// on a repository are skipped outside of one.
+func Run() []Check {
+	repo, err := hooks.Locate()
+	if err != nil {
+		return []Check{fail("repository", "cd into your repository first", "%v", err)}
+	}
+	checks := []Check{pass("repository", "git repository at %s", repo.Root)}
+
+	cfg, cfgCheck := checkConfig()
+	checks = append(checks, cfgCheck)
+	checks = append(checks, checkHooks(repo)...)
+	checks = append(checks, checkAI(cfg.AI))
+	return checks
+}
+
+func checkConfig() (config.Config, Check) {
+	const name = "config"
+	cfg, path, err := config.Load()
+	switch {
+	case errors.Is(err, config.ErrConfigNotFound):
+		return cfg, warn(name, "run `bartle init` to commit the team's rules", "no %s, using built-in defaults", filepath.Base(path))
+	case err != nil:
+		return cfg, fail(name, "fix the file; bartle lint refuses to run until it loads", "%v", err)
+	}
+	if err := lint.CheckConfig(cfg); err != nil {
+		return cfg, fail(name, "fix the file; bartle lint refuses to run until it loads", "%v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		global, _ := config.GlobalPath()
+		return cfg, warn(name, "run `bartle init` to commit the team's rules", "no %s, using only %s", filepath.Base(path), global)
+	}
+	return cfg, pass(name, "%s is valid", path)
+}
+
+// checkHooks reports on every hook bartle can install. Only commit-msg is
+// expected; the others are reported when present.
+func checkHooks(repo hooks.Repo) []Check {
+	var checks []Check
+	for _, hook := range hooks.Names() {
+		checks = append(checks, checkHook(repo, hook)...)
+	}
+	return checks
+}
+
+func checkHook(repo hooks.Repo, hook string) []Check {
+	name := hook + " hook"
+	path := repo.HookPath(hook)
+	install := "bartle install-hook"
+	if hook != hooks.CommitMsg {
+		install += " --type " + hook
+	}
+
+	var checks []Check
+	if repo.HooksPathSet {
+		// git only looks in core.hooksPath; a hook left in .git/hooks is dead.
+		shadowed := filepath.Join(repo.CommonDir, "hooks", hook)
+		if ours, _ := hooks.IsOurHook(shadowed); ours {
+			checks = append(checks, warn(name, "run `"+install+"` to install it into core.hooksPath, then remove "+shadowed,
+				"%s is ignored because core.hooksPath points to %s", shadowed, repo.HooksDir))
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if hook == hooks.CommitMsg {
+			return append(checks, fail(name, "run `"+install+"`", "not installed at %s", path))
+		}
+		return checks
+	}
+	if err != nil {
+		return append(checks, fail(name, "check the file's permissions", "read %s: %v", path, err))
+	}
+	script := string(data)
+
+	if !strings.Contains(script, hooks.MarkerPrefix) {
+		return append(checks, warn(name, "run `"+install+" --chain` to run bartle alongside it",
+			"%s exists but wasn't installed by bartle", path))
+	}
+
+	if v := hooks.MarkerVersion(script); v < hooks.HookVersion {
+		checks = append(checks, warn(name, "run `"+install+"` again to update it",
+			"installed by an older bartle (hook v%d, current v%d)", v, hooks.HookVersion))
+	} else {
+		checks = append(checks, pass(name, "installed at %s", path))
+	}
+
+	if st, err := os.Stat(path); err == nil && st.Mode().Perm()&0o111 == 0 {
+		checks = append(checks, fail(name, "run `chmod +x "+path+"`", "%s is not executable, so git skips it", path))
+	}
+
+	if cmd, ok := hooks.BartleCommand(script, hook); ok {
+		checks = append(checks, checkCommand(name, cmd))
+	}
+	return checks
+}
+
+// checkCommand makes sure the bartle command a hook invokes still exists.
+func checkCommand(name, cmd string) Check {
+	if strings.ContainsRune(cmd, filepath.Separator) {
+		st, err := os.Stat(cmd)
+		if err != nil || st.IsDir() || st.Mode().Perm()&0o111 == 0 {
+			return fail(name, "reinstall the hook with --absolute from the current bartle binary, or without it to use PATH",
+				"the hook runs %s, which no longer exists or isn't executable", cmd)
+		}
+		return pass(name, "runs %s", cmd)
+	}
+
+	resolved, err := exec.LookPath(cmd)
+	if err != nil {
+		return fail(name, "put bartle on PATH for git, or reinstall the hook with --absolute",
+			"the hook runs %q, which isn't on PATH", cmd)
+	}
+	return pass(name, "runs %s", resolved)
+}
+
+func checkAI(ai config.AI) Check {
+	const name = "ai credentials"
+	if !ai.Enabled {
+		return pass(name, "AI is disabled")
+	}
+	if _, err := ai.ResolveAPIKey(); err != nil {
+		hint := "set ai.api_key"
+		if env, ok := strings.CutPrefix(ai.APIKey, config.APIKeyFromEnv); ok {
+			hint = "export " + env + " in the environment git runs hooks from"
+		}
+		return fail(name, hint, "%v", err)
+	}
+	if !strings.HasPrefix(ai.APIKey, config.APIKeyFromEnv) {
+		return warn(name, "use api_key: env:<VAR> so the key stays out of the repository",
+			"ai.api_key is stored in plain text in the config")
+	}
+	return pass(name, "API key found in %s", strings.TrimPrefix(ai.APIKey, config.APIKeyFromEnv))
+}
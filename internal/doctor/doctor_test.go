@@ -0,0 +1,113 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/hooks"
+)
+
+func TestCheckHook(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(t *testing.T, repo hooks.Repo)
+		want      []Status
+		hooksPath string
+	}{
+		{
+			name:  "missing commit-msg hook fails",
+			setup: func(*testing.T, hooks.Repo) {},
+			want:  []Status{Fail},
+		},
+		{
+			name: "foreign hook warns",
+			setup: func(t *testing.T, repo hooks.Repo) {
+				writeHook(t, repo.HookPath(hooks.CommitMsg), "#!/bin/sh\nexit 0\n", 0o755)
+			},
+			want: []Status{Warn},
+		},
+		{
+			name: "old, non-executable hook with a missing binary",
+			setup: func(t *testing.T, repo hooks.Repo) {
+				script := "#!/bin/sh\n# BARTLE-HOOK v1\nexec /nonexistent/bartle lint --hook \"$1\"\n"
+				writeHook(t, repo.HookPath(hooks.CommitMsg), script, 0o644)
+			},
+			want: []Status{Warn, Fail, Fail},
+		},
+		{
+			name: "shadowed by core.hooksPath",
+			setup: func(t *testing.T, repo hooks.Repo) {
+				if err := hooks.InstallHook(filepath.Join(repo.CommonDir, "hooks", hooks.CommitMsg), hooks.CommitMsg, "sh", hooks.ChainNone); err != nil {
+					t.Fatal(err)
+				}
+			},
+			hooksPath: "shared",
+			want:      []Status{Warn, Fail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			repo := hooks.Repo{Root: root, GitDir: filepath.Join(root, ".git"), CommonDir: filepath.Join(root, ".git")}
+			repo.HooksDir = filepath.Join(repo.CommonDir, "hooks")
+			if tt.hooksPath != "" {
+				repo.HooksDir, repo.HooksPathSet = filepath.Join(root, tt.hooksPath), true
+			}
+			tt.setup(t, repo)
+
+			checks := checkHook(repo, hooks.CommitMsg)
+			var got []Status
+			for _, c := range checks {
+				got = append(got, c.Status)
+				if c.Status != Pass && c.Hint == "" {
+					t.Errorf("check %q has no hint", c.Message)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("statuses = %v, want %v (%+v)", got, tt.want, checks)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("statuses = %v, want %v (%+v)", got, tt.want, checks)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckAI(t *testing.T) {
+	t.Setenv("BARTLE_TEST_KEY", "secret")
+
+	tests := []struct {
+		ai   config.AI
+		want Status
+	}{
+		{config.AI{Enabled: false, APIKey: "env:BARTLE_TEST_MISSING"}, Pass},
+		{config.AI{Enabled: true, APIKey: "env:BARTLE_TEST_KEY"}, Pass},
+		{config.AI{Enabled: true, APIKey: "env:BARTLE_TEST_MISSING"}, Fail},
+		{config.AI{Enabled: true, APIKey: "sk-plain"}, Warn},
+		{config.AI{Enabled: true}, Fail},
+	}
+	for _, tt := range tests {
+		if got := checkAI(tt.ai); got.Status != tt.want {
+			t.Errorf("checkAI(%+v) = %v (%s), want %v", tt.ai, got.Status, got.Message, tt.want)
+		}
+	}
+}
+
+func writeHook(t *testing.T, path, script string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(script)+"\n"), mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,157 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrGitNotFound is returned when the git executable isn't on PATH.
+var ErrGitNotFound = errors.New("git executable not found on PATH")
+
+// ErrDetachedHead is returned by CurrentBranch when HEAD is detached.
+var ErrDetachedHead = errors.New("HEAD is detached")
+
+// Commit is a single commit with its full message.
+type Commit struct {
+	SHA     string
+	Message string
+}
+
+// Subject returns the first line of the commit message.
+func (c Commit) Subject() string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return subject
+}
+
+// ShortSHA returns the abbreviated commit hash used in reports.
+func (c Commit) ShortSHA() string {
+	if len(c.SHA) > 7 {
+		return c.SHA[:7]
+	}
+	return c.SHA
+}
+
+// Run executes git in the current directory and returns its stdout with the
+// trailing newline removed. Failures include git's stderr.
+func Run(args ...string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", ErrGitNotFound
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+	}
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// RevList returns the non-merge commits reachable from `to` but not from
+// `from`, oldest first. An empty `from` means the whole history of `to`.
+// Merge commits are skipped because their messages are generated by git.
+func RevList(from, to string) ([]string, error) {
+	rng := to
+	if from != "" {
+		rng = from + ".." + to
+	}
+	out, err := Run("rev-list", "--reverse", "--no-merges", rng, "--")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// CommitMessage returns the raw message of a commit.
+func CommitMessage(sha string) (string, error) {
+	return Run("log", "-1", "--format=%B", sha)
+}
+
+// MergedTags returns the tags reachable from rev.
+func MergedTags(rev string) ([]string, error) {
+	out, err := Run("tag", "--merged", rev)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// TagsAt returns the tags pointing at rev.
+func TagsAt(rev string) ([]string, error) {
+	out, err := Run("tag", "--points-at", rev)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// Commits returns every commit in from..to (see RevList) with its message.
+func Commits(from, to string) ([]Commit, error) {
+	shas, err := RevList(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return Load(shas)
+}
+
+// Load returns the commits with the given hashes, in the same order.
+func Load(shas []string) ([]Commit, error) {
+	commits := make([]Commit, 0, len(shas))
+	for _, sha := range shas {
+		msg, err := CommitMessage(sha)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, Commit{SHA: sha, Message: msg})
+	}
+	return commits, nil
+}
+
+// CurrentBranch returns the short name of the checked-out branch, or
+// ErrDetachedHead when HEAD doesn't point at a branch.
+func CurrentBranch() (string, error) {
+	if _, err := Run("symbolic-ref", "-q", "HEAD"); err != nil {
+		if errors.Is(err, ErrGitNotFound) {
+			return "", err
+		}
+		return "", ErrDetachedHead
+	}
+	return Run("symbolic-ref", "--short", "HEAD")
+}
+
+// StagedFiles returns the paths, relative to the repo root, that the next
+// commit will change. Inside a hook this honors the index git prepared for
+// the commit (e.g. with `git commit -a`).
+func StagedFiles() ([]string, error) {
+	return lines(Run("diff", "--cached", "--name-only"))
+}
+
+// ChangedFiles returns the paths, relative to the repo root, that a commit
+// changed.
+func ChangedFiles(sha string) ([]string, error) {
+	return lines(Run("diff-tree", "--no-commit-id", "--name-only", "-r", "--root", sha))
+}
+
+func lines(out string, err error) ([]string, error) {
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
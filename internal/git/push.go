@@ -0,0 +1,90 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RefUpdate is one line of what git feeds a pre-push hook on stdin.
+type RefUpdate struct {
+	LocalRef  string
+	LocalSHA  string
+	RemoteRef string
+	RemoteSHA string
+}
+
+// IsZeroSHA reports whether sha is git's all-zero object name, used for a
+// ref that doesn't exist on one side of a push.
+func IsZeroSHA(sha string) bool {
+	return sha != "" && strings.Trim(sha, "0") == ""
+}
+
+// Deletes reports whether the update deletes the remote ref.
+func (u RefUpdate) Deletes() bool { return IsZeroSHA(u.LocalSHA) }
+
+// Creates reports whether the update creates the remote ref.
+func (u RefUpdate) Creates() bool { return IsZeroSHA(u.RemoteSHA) }
+
+// RemoteBranch returns the branch name the update pushes to, or "" when the
+// remote ref isn't a branch (e.g. a tag).
+func (u RefUpdate) RemoteBranch() string {
+	name, ok := strings.CutPrefix(u.RemoteRef, "refs/heads/")
+	if !ok {
+		return ""
+	}
+	return name
+}
+
+// NewCommits returns the non-merge commits the update sends to the remote,
+// oldest first. For a new remote branch (or when the remote tip isn't known
+// locally, e.g. a force push) that is everything not already on any remote
+// and, when base is set, not reachable from base either. base bounds a first
+// push to an empty remote, where nothing is on a remote yet: the latest
+// release tag, say, so history that already shipped isn't linted.
+func (u RefUpdate) NewCommits(base string) ([]string, error) {
+	if u.Deletes() {
+		return nil, nil
+	}
+	if !u.Creates() {
+		if _, err := Run("cat-file", "-e", u.RemoteSHA+"^{commit}"); err == nil {
+			return RevList(u.RemoteSHA, u.LocalSHA)
+		}
+	}
+
+	args := []string{"rev-list", "--reverse", "--no-merges", u.LocalSHA, "--not", "--remotes"}
+	if base != "" {
+		args = append(args, base)
+	}
+	out, err := Run(append(args, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// ParsePushUpdates reads "<local ref> <local sha> <remote ref> <remote sha>"
+// lines as passed to the pre-push hook.
+func ParsePushUpdates(r io.Reader) ([]RefUpdate, error) {
+	var updates []RefUpdate
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		f := strings.Fields(line)
+		if len(f) != 4 {
+			return nil, fmt.Errorf("unexpected pre-push input %q", line)
+		}
+		updates = append(updates, RefUpdate{LocalRef: f[0], LocalSHA: f[1], RemoteRef: f[2], RemoteSHA: f[3]})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read pre-push input: %w", err)
+	}
+	return updates, nil
+}
@@ -0,0 +1,133 @@
+package git
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePushUpdates(t *testing.T) {
+	const zero = "0000000000000000000000000000000000000000"
+	input := "refs/heads/feat/x 1111111111111111111111111111111111111111 refs/heads/feat/x " + zero + "\n" +
+		"(delete) " + zero + " refs/heads/old 2222222222222222222222222222222222222222\n" +
+		"refs/tags/v1.0.0 3333333333333333333333333333333333333333 refs/tags/v1.0.0 " + zero + "\n"
+
+	updates, err := ParsePushUpdates(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updates) != 3 {
+		t.Fatalf("got %d updates, want 3", len(updates))
+	}
+
+	if u := updates[0]; !u.Creates() || u.Deletes() || u.RemoteBranch() != "feat/x" {
+		t.Fatalf("new branch parsed wrong: %+v", u)
+	}
+	if u := updates[1]; !u.Deletes() || u.Creates() {
+		t.Fatalf("deletion parsed wrong: %+v", u)
+	}
+	if u := updates[2]; u.RemoteBranch() != "" {
+		t.Fatalf("tag treated as branch: %+v", u)
+	}
+
+	if _, err := ParsePushUpdates(strings.NewReader("garbage\n")); err == nil {
+		t.Fatal("expected error for malformed input")
+	}
+}
+
+// tempRepo creates a git repository in a temporary directory and changes
+// into it.
+func tempRepo(t *testing.T) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, kv := range [][2]string{{"NAME", "Test"}, {"EMAIL", "test@example.com"}} {
+		t.Setenv("GIT_AUTHOR_"+kv[0], kv[1])
+		t.Setenv("GIT_COMMITTER_"+kv[0], kv[1])
+	}
+	mustRun(t, "init", "-q", "-b", "main")
+}
+
+func mustRun(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := Run(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func commitEmpty(t *testing.T, msg string) string {
+	t.Helper()
+	mustRun(t, "commit", "-q", "--allow-empty", "-m", msg)
+	return mustRun(t, "rev-parse", "HEAD")
+}
+
+func TestNewCommits(t *testing.T) {
+	const zero = "0000000000000000000000000000000000000000"
+	tempRepo(t)
+	a := commitEmpty(t, "a")
+	b := commitEmpty(t, "b")
+	mustRun(t, "tag", "v1.0.0")
+	c := commitEmpty(t, "c")
+	d := commitEmpty(t, "d")
+
+	tests := []struct {
+		name   string
+		update RefUpdate
+		remote string // refs/remotes/origin/main, when set
+		base   string
+		want   []string
+	}{
+		{
+			name:   "known remote tip",
+			update: RefUpdate{LocalSHA: d, RemoteSHA: b},
+			want:   []string{c, d},
+		},
+		{
+			name:   "new branch stops at remote-tracking refs",
+			update: RefUpdate{LocalSHA: d, RemoteSHA: zero},
+			remote: c,
+			want:   []string{d},
+		},
+		{
+			name:   "unknown remote tip falls back to remote-tracking refs",
+			update: RefUpdate{LocalSHA: d, RemoteSHA: "1111111111111111111111111111111111111111"},
+			remote: a,
+			want:   []string{b, c, d},
+		},
+		{
+			name:   "empty remote lints everything",
+			update: RefUpdate{LocalSHA: d, RemoteSHA: zero},
+			want:   []string{a, b, c, d},
+		},
+		{
+			name:   "empty remote stops at base",
+			update: RefUpdate{LocalSHA: d, RemoteSHA: zero},
+			base:   "v1.0.0",
+			want:   []string{c, d},
+		},
+		{
+			name:   "deletion",
+			update: RefUpdate{LocalSHA: zero, RemoteSHA: d},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.remote != "" {
+				mustRun(t, "update-ref", "refs/remotes/origin/main", tt.remote)
+				defer mustRun(t, "update-ref", "-d", "refs/remotes/origin/main")
+			}
+			got, err := tt.update.NewCommits(tt.base)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("NewCommits = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Chain says when a hook that was installed before bartle runs relative to
+// bartle.
+type Chain string
+
+const (
+	ChainNone   Chain = ""
+	ChainBefore Chain = "before"
+	ChainAfter  Chain = "after"
+)
+
+// ChainedSuffix is appended to a hook's path to keep the original hook
+// around while bartle's script chains to it.
+const ChainedSuffix = ".bartle-chained"
+
+// chainMarker records the chain order in bartle's script so reinstalls keep it.
+const chainMarker = "# BARTLE-CHAIN"
+
+// ParseChain validates a chain order given on the command line.
+func ParseChain(s string) (Chain, error) {
+	switch c := Chain(strings.ToLower(s)); c {
+	case ChainNone, ChainBefore, ChainAfter:
+		return c, nil
+	}
+	return ChainNone, fmt.Errorf("invalid chain order %q (allowed: before|after)", s)
+}
+
+// ChainedPath is where a chained original hook is kept.
+func ChainedPath(hookPath string) string {
+	return hookPath + ChainedSuffix
+}
+
+// ChainOf returns the chain order recorded in a bartle hook script.
+func ChainOf(script string) Chain {
+	for _, line := range strings.Split(script, "\n") {
+		if order, ok := strings.CutPrefix(line, chainMarker+" "); ok {
+			if c, err := ParseChain(strings.TrimSpace(order)); err == nil {
+				return c
+			}
+		}
+	}
+	return ChainNone
+}
+
+// prepareChain moves a foreign hook aside when chaining is requested and
+// works out the order the new script should use.
+func prepareChain(hookPath string, chain Chain) (Chain, error) {
+	chained := ChainedPath(hookPath)
+	_, err := os.Lstat(chained)
+	hasChained := err == nil
+
+	exists, isOurs, err := CheckExisting(hookPath)
+	if err != nil {
+		return ChainNone, err
+	}
+
+	if exists && isOurs {
+		if !hasChained {
+			return chain, nil
+		}
+		if chain != ChainNone {
+			return chain, nil
+		}
+		// A plain reinstall must not orphan the chained hook.
+		data, err := os.ReadFile(hookPath)
+		if err != nil {
+			return ChainNone, fmt.Errorf("read existing hook: %w", err)
+		}
+		if c := ChainOf(string(data)); c != ChainNone {
+			return c, nil
+		}
+		return ChainBefore, nil
+	}
+
+	if chain == ChainNone || !exists {
+		return chain, nil
+	}
+	if hasChained {
+		return ChainNone, fmt.Errorf("%s already exists; move it away before chaining %s", chained, hookPath)
+	}
+	if err := os.Rename(hookPath, chained); err != nil {
+		return ChainNone, fmt.Errorf("preserve existing hook: %w", err)
+	}
+	return chain, nil
+}
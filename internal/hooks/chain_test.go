@@ -0,0 +1,80 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChainRoundTrip(t *testing.T) {
+	for _, chain := range []Chain{ChainBefore, ChainAfter} {
+		t.Run(string(chain), func(t *testing.T) {
+			hookPath := filepath.Join(t.TempDir(), CommitMsg)
+			original := "#!/bin/sh\necho original\n"
+			if err := os.WriteFile(hookPath, []byte(original), 0o750); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := InstallHook(hookPath, CommitMsg, "bartle", chain); err != nil {
+				t.Fatalf("install: %v", err)
+			}
+			script, err := os.ReadFile(hookPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ChainOf(string(script)); got != chain {
+				t.Fatalf("ChainOf = %q, want %q", got, chain)
+			}
+			bartle := strings.Index(string(script), "bartle lint")
+			chained := strings.Index(string(script), `"$chained" "$@"`)
+			if (chain == ChainBefore) != (chained < bartle) {
+				t.Fatalf("chained hook runs in the wrong order:\n%s", script)
+			}
+
+			// A plain reinstall keeps the chain.
+			if err := InstallHook(hookPath, CommitMsg, "bartle", ChainNone); err != nil {
+				t.Fatalf("reinstall: %v", err)
+			}
+			script, _ = os.ReadFile(hookPath)
+			if got := ChainOf(string(script)); got != chain {
+				t.Fatalf("reinstall dropped the chain: %q", got)
+			}
+
+			res, err := UninstallHook(hookPath, false)
+			if err != nil {
+				t.Fatalf("uninstall: %v", err)
+			}
+			if !res.Restored {
+				t.Fatalf("original not restored: %+v", res)
+			}
+			data, err := os.ReadFile(hookPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			st, _ := os.Stat(hookPath)
+			if string(data) != original || st.Mode().Perm() != 0o750 {
+				t.Fatalf("restored hook differs: %q %v", data, st.Mode().Perm())
+			}
+			if _, err := os.Stat(ChainedPath(hookPath)); !os.IsNotExist(err) {
+				t.Fatalf("chained copy left behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestPrePushChainCopiesStdin(t *testing.T) {
+	hookPath := filepath.Join(t.TempDir(), PrePush)
+	if err := os.WriteFile(hookPath, []byte("#!/bin/sh\ncat\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := InstallHook(hookPath, PrePush, "bartle", ChainBefore); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	script, _ := os.ReadFile(hookPath)
+	for _, want := range []string{`cat >"$input"`, `"$chained" "$@" <"$input"`, `bartle pre-push "$@" <"$input"`} {
+		if !strings.Contains(string(script), want) {
+			t.Fatalf("script missing %q:\n%s", want, script)
+		}
+	}
+}
@@ -1,41 +1,73 @@
 package hooks
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
 
 const (
-	// Marker lets us detect whether a hook file was written by Bartle.
-	BartleHookMarker = "# BARTLE-HOOK v1"
+	// MarkerPrefix lets us detect whether a hook file was written by Bartle,
+	// whatever version of Bartle wrote it.
+	MarkerPrefix = "# BARTLE-HOOK"
+
+	// HookVersion is bumped whenever the generated script changes in a way
+	// that matters to users; BartleHookMarker embeds it.
+	HookVersion      = 2
+	BartleHookMarker = "# BARTLE-HOOK v2"
 )
 
-// RepoRootFromCwd walks upward from CWD until it finds a .git directory.
-func RepoRootFromCwd() (string, error) {
-	start, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("get working directory: %w", err)
-	}
-	dir := start
-	for {
-		if st, err := os.Stat(filepath.Join(dir, ".git")); err == nil && st.IsDir() {
-			return dir, nil
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			return "", errors.New("not inside a git repository (run `git init` first)")
-		}
-		dir = parent
+// Git hooks bartle knows how to install.
+const (
+	CommitMsg        = "commit-msg"
+	PrepareCommitMsg = "prepare-commit-msg"
+	PrePush          = "pre-push"
+)
+
+// hookSpec describes the script bartle writes for one hook.
+type hookSpec struct {
+	comment string // explains what the hook does
+	args    string // appended to the bartle command
+	stdin   bool   // git feeds the hook on stdin, which a chained hook needs too
+}
+
+var hookSpecs = map[string]hookSpec{
+	CommitMsg: {
+		comment: `# Pass the path to the commit message file to bartle for linting.
+# bartle decides whether a failure blocks the commit (hook.block_on_fail)
+# and may rewrite the file with automatic fixes (hook.auto_apply).`,
+		args: `lint --hook "$1"`,
+	},
+	PrepareCommitMsg: {
+		comment: `# Pre-fill the message from the branch name (branch.extract). bartle
+# leaves merges, squashes, amends and -m/-F messages alone.`,
+		args: `prepare-commit-msg "$@"`,
+	},
+	PrePush: {
+		comment: `# Lint every commit being pushed and the names of the branches pushed
+# to. git passes the ref updates on stdin.`,
+		args:  `pre-push "$@"`,
+		stdin: true,
+	},
+}
+
+// Names returns the hooks bartle can install, sorted.
+func Names() []string {
+	names := make([]string, 0, len(hookSpecs))
+	for name := range hookSpecs {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
 }
 
-// HookPath returns the commit-msg hook path for a repo root.
-func HookPath(repoRoot string) string {
-	return filepath.Join(repoRoot, ".git", "hooks", "commit-msg")
+// Supported reports whether bartle can install the named hook.
+func Supported(name string) bool {
+	_, ok := hookSpecs[name]
+	return ok
 }
 
 // CheckExisting reports (exists, isOurs, err) for a hook path.
@@ -52,24 +84,65 @@ func CheckExisting(hookPath string) (bool, bool, error) {
 }
 
 func containsMarker(s string) bool {
-	return len(s) > 0 && (func() bool {
-		for i := 0; i+len(BartleHookMarker) <= len(s); i++ {
-			if s[i:i+len(BartleHookMarker)] == BartleHookMarker {
-				return true
-			}
+	return strings.Contains(s, MarkerPrefix)
+}
+
+// MarkerVersion returns the hook version recorded in a Bartle hook script,
+// or 0 if the script has no Bartle marker.
+func MarkerVersion(script string) int {
+	i := strings.Index(script, MarkerPrefix+" v")
+	if i < 0 {
+		return 0
+	}
+	rest := script[i+len(MarkerPrefix)+2:]
+	v := 0
+	for _, r := range rest {
+		if r < '0' || r > '9' {
+			break
+		}
+		v = v*10 + int(r-'0')
+	}
+	return v
+}
+
+// BartleCommand returns the bartle command a hook script written for the
+// named hook invokes: "bartle", or an absolute path with --absolute.
+func BartleCommand(script, name string) (string, bool) {
+	spec, ok := hookSpecs[name]
+	if !ok {
+		return "", false
+	}
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "exec ")
+		if cmd, _, found := strings.Cut(line, " "+spec.args); found && cmd != "" {
+			return cmd, true
 		}
-		return false
-	}())
+	}
+	return "", false
 }
 
-// InstallCommitMsgHook writes the hook script atomically and makes it executable.
-func InstallCommitMsgHook(hookPath, bartleCmd string) error {
+// InstallHook writes the script for the named hook atomically and makes it
+// executable.
+//
+// With a chain order, a hook bartle didn't write is moved to
+// ChainedPath(hookPath) and run before or after bartle. Reinstalling over a
+// chained install keeps the chain (and its order, unless a new one is given).
+func InstallHook(hookPath, name, bartleCmd string, chain Chain) error {
+	if !Supported(name) {
+		return fmt.Errorf("unsupported hook %q (supported: %s)", name, strings.Join(Names(), ", "))
+	}
+
 	hooksDir := filepath.Dir(hookPath)
 	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
 		return fmt.Errorf("create hooks directory: %w", err)
 	}
 
-	script := hookScript(bartleCmd)
+	chain, err := prepareChain(hookPath, chain)
+	if err != nil {
+		return err
+	}
+
+	script := hookScript(name, bartleCmd, chain)
 
 	tmpPath := hookPath + ".tmp"
 	if err := os.WriteFile(tmpPath, []byte(script), 0o755); err != nil {
@@ -87,17 +160,36 @@ func InstallCommitMsgHook(hookPath, bartleCmd string) error {
 	return nil
 }
 
-func hookScript(bartleCmd string) string {
-	return fmt.Sprintf(`#!/bin/sh
-%s
+func hookScript(name, bartleCmd string, chain Chain) string {
+	spec := hookSpecs[name]
+	run := bartleCmd + " " + spec.args
 
-# Bartle commit-msg hook
-%s
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "#!/bin/sh\nset -e\n\n# Bartle %s hook\n%s\n", name, BartleHookMarker)
+	if chain == ChainNone {
+		fmt.Fprintf(&sb, "\n%s\nexec %s\n", spec.comment, run)
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "%s %s\n\n", chainMarker, chain)
+	fmt.Fprintf(&sb, "# The %s hook that was here before bartle runs %s it.\n", name, chain)
+	fmt.Fprintf(&sb, "chained=\"$(dirname \"$0\")/%s%s\"\n", name, ChainedSuffix)
 
-# Pass the path to the commit message file to bartle for linting.
-# If lint fails, exit non-zero to block the commit.
-exec %s lint "$1"
-`, "set -e", BartleHookMarker, bartleCmd)
+	runChained := `"$chained" "$@"`
+	if spec.stdin {
+		// Both hooks need what git wrote to stdin, so keep a copy.
+		sb.WriteString("input=\"$(mktemp)\"\ntrap 'rm -f \"$input\"' EXIT\ncat >\"$input\"\n")
+		runChained += ` <"$input"`
+		run += ` <"$input"`
+	}
+	runChained = `if [ -x "$chained" ]; then ` + runChained + `; fi`
+
+	steps := []string{spec.comment + "\n" + run, runChained}
+	if chain == ChainBefore {
+		steps[0], steps[1] = steps[1], steps[0]
+	}
+	fmt.Fprintf(&sb, "\n%s\n\n%s\n", steps[0], steps[1])
+	return sb.String()
 }
 
 func IsOurHook(hookPath string) (bool, error) {
@@ -108,46 +200,60 @@ func IsOurHook(hookPath string) (bool, error) {
 		}
 		return false, fmt.Errorf("read hook: %w", err)
 	}
-	return strings.Contains(string(data), BartleHookMarker), nil
+	return containsMarker(string(data)), nil
 }
 
-// UninstallCommitMsgHook removes the commit-msg hook.
-// Returns (changed, backupPath, err).
-// - If the hook doesn't exist → (false, "", nil)
-// - If the hook is ours → delete and return (true, "", nil)
+// Uninstalled describes what UninstallHook did.
+type Uninstalled struct {
+	Changed    bool
+	BackupPath string // set when a foreign hook was moved aside with force
+	Restored   bool   // set when a chained hook was put back in place
+}
+
+// UninstallHook removes a hook.
+// - If the hook doesn't exist → nothing changes
+// - If the hook is ours → delete it, or put back the hook it chained to
 // - If the hook is not ours:
 //   - force=false  → error (won't remove other tools' hooks)
-//   - force=true   → move to .bak.<timestamp> and return (true, backupPath, nil)
-func UninstallCommitMsgHook(hookPath string, force bool) (bool, string, error) {
+//   - force=true   → move to .bak.<timestamp> and report BackupPath
+func UninstallHook(hookPath string, force bool) (Uninstalled, error) {
 	// No hook present
 	if _, err := os.Stat(hookPath); err != nil {
 		if os.IsNotExist(err) {
-			return false, "", nil
+			return Uninstalled{}, nil
 		}
-		return false, "", fmt.Errorf("stat hook: %w", err)
+		return Uninstalled{}, fmt.Errorf("stat hook: %w", err)
 	}
 
 	isOurs, err := IsOurHook(hookPath)
 	if err != nil {
-		return false, "", err
+		return Uninstalled{}, err
 	}
 
-	// If it's ours, just remove it.
+	// If it's ours, remove it or restore the original it chained to. The
+	// rename keeps the original's content and mode untouched.
 	if isOurs {
+		chained := ChainedPath(hookPath)
+		if _, err := os.Lstat(chained); err == nil {
+			if err := os.Rename(chained, hookPath); err != nil {
+				return Uninstalled{}, fmt.Errorf("restore chained hook: %w", err)
+			}
+			return Uninstalled{Changed: true, Restored: true}, nil
+		}
 		if err := os.Remove(hookPath); err != nil {
-			return false, "", fmt.Errorf("remove hook: %w", err)
+			return Uninstalled{}, fmt.Errorf("remove hook: %w", err)
 		}
-		return true, "", nil
+		return Uninstalled{Changed: true}, nil
 	}
 
 	// Not ours: respect --force, and back it up.
 	if !force {
-		return false, "", fmt.Errorf("%s exists and is not managed by Bartle (use --force to remove)", hookPath)
+		return Uninstalled{}, fmt.Errorf("%s exists and is not managed by Bartle (use --force to remove)", hookPath)
 	}
 
 	backupPath := hookPath + ".bak." + time.Now().Format("20060102150405")
 	if err := os.Rename(hookPath, backupPath); err != nil {
-		return false, "", fmt.Errorf("backup existing hook: %w", err)
+		return Uninstalled{}, fmt.Errorf("backup existing hook: %w", err)
 	}
-	return true, backupPath, nil
+	return Uninstalled{Changed: true, BackupPath: backupPath}, nil
 }
@@ -0,0 +1,125 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/git"
+)
+
+// ErrNotInRepo is returned by Locate outside of a git working tree.
+var ErrNotInRepo = errors.New("not inside a git repository (run `git init` first)")
+
+// Repo is where git keeps things for the working tree we're in. In a plain
+// clone GitDir and CommonDir are both <Root>/.git. In a linked worktree or a
+// submodule .git is a file pointing at GitDir, and worktrees share
+// CommonDir (and with it the hooks) with the main checkout.
+type Repo struct {
+	Root      string // top of the working tree
+	GitDir    string
+	CommonDir string
+	// HooksDir is core.hooksPath when set, otherwise <CommonDir>/hooks.
+	HooksDir string
+	// HooksPathSet reports whether HooksDir comes from core.hooksPath.
+	HooksPathSet bool
+}
+
+// HookPath returns the path of the named hook.
+func (r Repo) HookPath(name string) string {
+	return filepath.Join(r.HooksDir, name)
+}
+
+// Locate finds the repository around the working directory.
+func Locate() (Repo, error) {
+	start, err := os.Getwd()
+	if err != nil {
+		return Repo{}, fmt.Errorf("get working directory: %w", err)
+	}
+
+	r, err := findGitDirs(start)
+	if err != nil {
+		return Repo{}, err
+	}
+
+	// `git config` honors includes and every config level, so ask git
+	// rather than parsing config files ourselves. An unset key fails.
+	setting, _ := git.Run("-C", r.Root, "config", "--path", "--get", "core.hooksPath")
+	r.HooksDir, r.HooksPathSet = resolveHooksDir(r.Root, r.CommonDir, setting)
+	return r, nil
+}
+
+// findGitDirs walks upward from start to the first .git, which is either the
+// git dir itself or a file containing "gitdir: <path>".
+func findGitDirs(start string) (Repo, error) {
+	dir := start
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		st, err := os.Stat(dotGit)
+		if err == nil {
+			r := Repo{Root: dir, GitDir: dotGit}
+			if !st.IsDir() {
+				gitDir, err := readGitFile(dotGit)
+				if err != nil {
+					return Repo{}, err
+				}
+				r.GitDir = gitDir
+			}
+			r.CommonDir = commonDir(r.GitDir)
+			return r, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Repo{}, ErrNotInRepo
+		}
+		dir = parent
+	}
+}
+
+// readGitFile resolves a .git file; a relative gitdir is relative to the
+// directory holding the file.
+func readGitFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("%s is not a git dir or a \"gitdir:\" file", path)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return filepath.Clean(gitDir), nil
+}
+
+// commonDir follows the commondir file linked worktrees have; without one
+// the git dir is its own common dir.
+func commonDir(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	dir := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(gitDir, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// resolveHooksDir applies a core.hooksPath setting. Like git, a relative
+// path is taken from the top of the working tree.
+func resolveHooksDir(root, commonDir, setting string) (string, bool) {
+	setting = strings.TrimSpace(setting)
+	if setting == "" {
+		return filepath.Join(commonDir, "hooks"), false
+	}
+	if !filepath.IsAbs(setting) {
+		setting = filepath.Join(root, setting)
+	}
+	return filepath.Clean(setting), true
+}
@@ -0,0 +1,72 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindGitDirs(t *testing.T) {
+	base := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// main checkout with a linked worktree and a submodule
+	mainGit := filepath.Join(base, "main", ".git")
+	write(filepath.Join(mainGit, "HEAD"), "ref: refs/heads/main\n")
+	write(filepath.Join(mainGit, "worktrees", "wt", "commondir"), "../..\n")
+	write(filepath.Join(base, "wt", ".git"), "gitdir: "+filepath.Join(mainGit, "worktrees", "wt")+"\n")
+	write(filepath.Join(base, "main", "sub", ".git"), "gitdir: ../.git/modules/sub\n")
+	if err := os.MkdirAll(filepath.Join(base, "main", "sub", "deep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		start     string
+		root      string
+		gitDir    string
+		commonDir string
+	}{
+		{"plain", filepath.Join(base, "main"), filepath.Join(base, "main"), mainGit, mainGit},
+		{"worktree", filepath.Join(base, "wt"), filepath.Join(base, "wt"), filepath.Join(mainGit, "worktrees", "wt"), mainGit},
+		{"submodule", filepath.Join(base, "main", "sub", "deep"), filepath.Join(base, "main", "sub"), filepath.Join(mainGit, "modules", "sub"), filepath.Join(mainGit, "modules", "sub")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := findGitDirs(tt.start)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r.Root != tt.root || r.GitDir != tt.gitDir || r.CommonDir != tt.commonDir {
+				t.Fatalf("got %+v\nwant root=%s gitdir=%s commondir=%s", r, tt.root, tt.gitDir, tt.commonDir)
+			}
+		})
+	}
+}
+
+func TestResolveHooksDir(t *testing.T) {
+	tests := []struct {
+		setting string
+		want    string
+		wantSet bool
+	}{
+		{"", "/repo/.git/hooks", false},
+		{".githooks", "/repo/.githooks", true},
+		{"/shared/hooks", "/shared/hooks", true},
+	}
+	for _, tt := range tests {
+		got, set := resolveHooksDir("/repo", "/repo/.git", tt.setting)
+		if got != tt.want || set != tt.wantSet {
+			t.Errorf("resolveHooksDir(%q) = %q, %v; want %q, %v", tt.setting, got, set, tt.want, tt.wantSet)
+		}
+	}
+}
@@ -1,17 +1,81 @@
 package lint
 
 import (
-	"fmt"
 	"strings"
 	"unicode"
 	"unicode/utf8"
+
+	"github.com/RyanTalbot/bartle/internal/branch"
+	"github.com/RyanTalbot/bartle/internal/config"
 )
 
+func init() { RegisterStyle(conventionalStyle{}) }
+
+// conventionalStyle implements https://www.conventionalcommits.org.
+type conventionalStyle struct{}
+
+func (conventionalStyle) Name() string { return "conventional" }
+
+func (conventionalStyle) Description() string {
+	return "Conventional Commits: type(scope)!: subject, with optional body and footers"
+}
+
+func (conventionalStyle) Example(rules config.Rules) string {
+	if rules.ScopeRequired {
+		return "feat(api): add pagination"
+	}
+	return "feat: add pagination"
+}
+
+func (conventionalStyle) Validate(m Message, rules config.Rules) []Diagnostic {
+	return validateConventional(m, rules)
+}
+
+func (conventionalStyle) FixHeader(header string, rules config.Rules) (string, []Fix) {
+	return fixConventionalHeader(header, rules)
+}
+
+// Prefill starts the header with the branch's type and scope and references
+// the ticket in a footer.
+func (conventionalStyle) Prefill(info branch.Info, rules config.Rules) string {
+	var header string
+	if info.Type != "" && (len(rules.Types) == 0 || inStringSet(rules.Types, info.Type)) {
+		header = info.Type
+		if info.Scope != "" {
+			header += "(" + info.Scope + ")"
+		}
+		header += ": "
+	}
+
+	if looksLikeTicket(info.Ticket) {
+		return header + "\n\nRefs: " + info.Ticket
+	}
+	return header
+}
+
 type Parsed struct {
-	Type     string
-	Scope    string
-	Subject  string
-	Breaking bool // optional future use
+	Type    string
+	Scope   string
+	Subject string
+	// Breaking is the merged breaking-change signal: set by either the `!`
+	// marker in the header or a BREAKING CHANGE footer.
+	Breaking       bool
+	BreakingMarker bool   // `!` before the ':' in the header
+	BreakingNote   string // value of the BREAKING CHANGE footer, if any
+}
+
+// ParseConventional parses the header of m and merges in any
+// BREAKING CHANGE footer.
+func ParseConventional(m Message) (Parsed, bool) {
+	parsed, ok := ParseConventionalLine(m.Header)
+	if !ok {
+		return Parsed{}, false
+	}
+	if f, found := m.BreakingFooter(); found {
+		parsed.Breaking = true
+		parsed.BreakingNote = strings.TrimSpace(f.Value)
+	}
+	return parsed, true
 }
 
 func ParseConventionalLine(line string) (Parsed, bool) {
@@ -56,6 +120,7 @@ func ParseConventionalLine(line string) (Parsed, bool) {
 
 	if cursor < length && message[cursor] == '!' {
 		parsed.Breaking = true
+		parsed.BreakingMarker = true
 		cursor++
 	}
 
@@ -85,6 +150,25 @@ func ParseConventionalLine(line string) (Parsed, bool) {
 	return parsed, true
 }
 
+// conventionalSeparator returns the whitespace between ':' and the subject of
+// a header that ParseConventionalLine accepted.
+func conventionalSeparator(header string, parsed Parsed) string {
+	colon := len(parsed.Type)
+	if parsed.Scope != "" || strings.HasPrefix(header[colon:], "(") {
+		colon += len(parsed.Scope) + 2
+	}
+	if parsed.BreakingMarker {
+		colon++
+	}
+	return header[colon+1 : len(header)-len(parsed.Subject)]
+}
+
+// hasFullStop reports whether a subject ends with a single period
+// (an ellipsis is left alone).
+func hasFullStop(subject string) bool {
+	return strings.HasSuffix(subject, ".") && !strings.HasSuffix(subject, "..") && len(subject) > 1
+}
+
 func FormatExample(requireScope bool) string {
 	if requireScope {
 		return "type(scope): subject"
@@ -92,6 +176,97 @@ func FormatExample(requireScope bool) string {
 	return "type: subject"
 }
 
-func Errorf(format string, args ...any) string {
-	return " - " + fmt.Sprintf(format, args...)
+func validateConventional(m Message, rules config.Rules) []Diagnostic {
+	var out []Diagnostic
+	line := m.Header
+
+	parsed, ok := ParseConventional(m)
+	if !ok {
+		colonIdx := strings.Index(line, ":")
+		if colonIdx < 0 {
+			out = append(out, diag(RuleHeaderFormat, m.HeaderSpan, "missing ':' separator (e.g., %s)", FormatExample(rules.ScopeRequired)))
+			return out
+		}
+
+		subject := strings.TrimSpace(line[colonIdx+1:])
+		if subject == "" {
+			out = append(out, diag(RuleSubjectEmpty, m.HeaderRange(colonIdx, len(line)), "empty subject after ':'"))
+		}
+
+		hasOpen := strings.Contains(line, "(")
+		hasClose := strings.Contains(line, ")")
+		if hasOpen && !hasClose {
+			open := strings.Index(line, "(")
+			out = append(out, diag(RuleHeaderFormat, m.HeaderRange(open, open+1), "unclosed scope '(' — expected ')': e.g., %s", FormatExample(true)))
+		}
+		if rules.ScopeRequired && !hasOpen {
+			out = append(out, diag(RuleScopeRequired, m.HeaderRange(0, colonIdx), "missing scope (e.g., type(scope): subject)"))
+		}
+
+		if len(out) == 0 {
+			out = append(out, diag(RuleHeaderFormat, m.HeaderSpan, "not conventional format (e.g., %s)", FormatExample(rules.ScopeRequired)))
+		}
+		return out
+	}
+
+	typeSpan := m.HeaderRange(0, len(parsed.Type))
+	subjectStart := len(line) - len(parsed.Subject)
+
+	if parsed.Type != strings.ToLower(parsed.Type) {
+		out = append(out, diag(RuleTypeCase, typeSpan, "type must be lowercase (got %q)", parsed.Type))
+	}
+
+	if !inStringSet(rules.Types, parsed.Type) {
+		out = append(out, diag(RuleTypeEnum, typeSpan, "type %q not allowed (choose one of: %s)", parsed.Type, strings.Join(rules.Types, ", ")))
+	}
+
+	if rules.ScopeRequired && parsed.Scope == "" {
+		out = append(out, diag(RuleScopeRequired, typeSpan, "scope required (e.g., %s)", "type(scope): subject"))
+	}
+
+	out = append(out, validateHeaderLength(m, rules)...)
+
+	if rules.LowercaseStart && len(parsed.Subject) > 0 && isUpper(rune(parsed.Subject[0])) {
+		out = append(out, diag(RuleSubjectCase, m.HeaderRange(subjectStart, len(line)), "subject should start lowercase"))
+	}
+
+	if sep := conventionalSeparator(line, parsed); sep != " " {
+		out = append(out, diag(RuleSubjectSpace, m.HeaderRange(subjectStart-len(sep), subjectStart), "expected exactly one space after ':'"))
+	}
+
+	out = append(out, validateFullStop(m, parsed.Subject)...)
+
+	out = append(out, validateBreaking(m, parsed, rules)...)
+
+	return out
+}
+
+func fixConventionalHeader(header string, rules config.Rules) (string, []Fix) {
+	parsed, ok := ParseConventionalLine(header)
+	if !ok {
+		return header, nil
+	}
+
+	var fixes []Fix
+	if lower := strings.ToLower(parsed.Type); lower != parsed.Type {
+		fixes = append(fixes, Fix{Rule: RuleTypeCase, Description: "lowercased type " + parsed.Type + " → " + lower})
+		parsed.Type = lower
+	}
+
+	prefix := parsed.Type
+	if parsed.Scope != "" {
+		prefix += "(" + parsed.Scope + ")"
+	}
+	if parsed.BreakingMarker {
+		prefix += "!"
+	}
+
+	if conventionalSeparator(header, parsed) != " " {
+		fixes = append(fixes, Fix{Rule: RuleSubjectSpace, Description: "put exactly one space after ':'"})
+	}
+
+	subject, subjectFixes := fixSubject(parsed.Subject, rules.LowercaseStart)
+	fixes = append(fixes, subjectFixes...)
+
+	return prefix + ": " + subject, fixes
 }
@@ -0,0 +1,138 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/branch"
+	"github.com/RyanTalbot/bartle/internal/config"
+)
+
+func init() { RegisterStyle(customStyle{}) }
+
+// customStyle validates the header against rules.pattern.
+type customStyle struct{}
+
+func (customStyle) Name() string { return "custom" }
+
+func (customStyle) Description() string {
+	return "Header must match the rules.pattern regex; named groups feed the other rules"
+}
+
+func (customStyle) Example(rules config.Rules) string {
+	if rules.Pattern != "" {
+		return "matches " + rules.Pattern
+	}
+	return "depends on rules.pattern"
+}
+
+func (customStyle) Validate(m Message, rules config.Rules) []Diagnostic {
+	return validateCustom(m, rules)
+}
+
+// FixHeader leaves the header alone: without knowing what the pattern means
+// there is nothing safe to repair.
+func (customStyle) FixHeader(header string, _ config.Rules) (string, []Fix) {
+	return header, nil
+}
+
+// Prefill leaves the message empty for the same reason.
+func (customStyle) Prefill(branch.Info, config.Rules) string { return "" }
+
+// Named capture groups understood by the custom style. Any of them may be
+// left out of rules.pattern; the matching rule is then skipped.
+const (
+	groupType     = "type"
+	groupScope    = "scope"
+	groupTicket   = "ticket"
+	groupSubject  = "subject"
+	groupBreaking = "breaking"
+)
+
+// MatchPattern matches header against a rules.pattern regex and returns the
+// named capture groups that took part in the match.
+func MatchPattern(pattern, header string) (map[string]string, bool, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid rules.pattern %q: %w", pattern, err)
+	}
+
+	idx := re.FindStringSubmatchIndex(header)
+	if idx == nil {
+		return nil, false, nil
+	}
+
+	groups := map[string]string{}
+	for i, name := range re.SubexpNames() {
+		if name == "" || idx[2*i] < 0 {
+			continue
+		}
+		groups[name] = header[idx[2*i]:idx[2*i+1]]
+	}
+	return groups, true, nil
+}
+
+func validateCustom(m Message, rules config.Rules) []Diagnostic {
+	var out []Diagnostic
+	line := m.Header
+
+	if rules.Pattern == "" {
+		return append(out, diag(RuleHeaderFormat, m.HeaderSpan, "custom style requires rules.pattern"))
+	}
+
+	re, err := regexp.Compile(rules.Pattern)
+	if err != nil {
+		return append(out, diag(RuleHeaderFormat, m.HeaderSpan, "invalid rules.pattern %q: %v", rules.Pattern, err))
+	}
+
+	idx := re.FindStringSubmatchIndex(line)
+	if idx == nil {
+		return append(out, diag(RuleHeaderFormat, m.HeaderSpan, "header does not match rules.pattern %s", rules.Pattern))
+	}
+
+	// group returns the text and span of a named group, and whether it matched.
+	group := func(name string) (string, Span, bool) {
+		i := re.SubexpIndex(name)
+		if i < 0 || idx[2*i] < 0 {
+			return "", m.HeaderSpan, false
+		}
+		return line[idx[2*i]:idx[2*i+1]], m.HeaderRange(idx[2*i], idx[2*i+1]), true
+	}
+	hasGroup := func(name string) bool { return re.SubexpIndex(name) >= 0 }
+
+	typ, typeSpan, ok := group(groupType)
+	if ok && len(rules.Types) > 0 && !inStringSet(rules.Types, typ) {
+		out = append(out, diag(RuleTypeEnum, typeSpan, "type %q not allowed (choose one of: %s)", typ, strings.Join(rules.Types, ", ")))
+	}
+
+	scope, _, _ := group(groupScope)
+	if hasGroup(groupScope) && rules.ScopeRequired && scope == "" {
+		out = append(out, diag(RuleScopeRequired, m.HeaderSpan, "scope required"))
+	}
+
+	if ticket, span, ok := group(groupTicket); ok && !looksLikeTicket(ticket) {
+		out = append(out, diag(RuleTicketFormat, span, "ticket %q doesn't look like a ticket (e.g., ABC-123)", ticket))
+	}
+
+	subject, subjectSpan, _ := group(groupSubject)
+	if hasGroup(groupSubject) && strings.TrimSpace(subject) == "" {
+		out = append(out, diag(RuleSubjectEmpty, subjectSpan, "empty subject"))
+	}
+	if rules.LowercaseStart && len(subject) > 0 && isUpper(rune(subject[0])) {
+		out = append(out, diag(RuleSubjectCase, subjectSpan, "subject should start lowercase"))
+	}
+
+	out = append(out, validateHeaderLength(m, rules)...)
+
+	breaking, _, _ := group(groupBreaking)
+	parsed := Parsed{
+		Type:           typ,
+		Scope:          scope,
+		Subject:        subject,
+		BreakingMarker: breaking != "",
+	}
+	out = append(out, validateBreaking(m, parsed, rules)...)
+
+	return out
+}
@@ -0,0 +1,157 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+)
+
+type Severity string
+
+const (
+	SeverityError Severity = "error"
+	SeverityWarn  Severity = "warn"
+	SeverityOff   Severity = "off"
+)
+
+// Rule identifies a single check. IDs are what users put under
+// rules.severity in .bartle.yaml.
+type Rule struct {
+	ID          string
+	Description string
+	Default     Severity
+}
+
+const (
+	RuleHeaderEmpty       = "header-empty"
+	RuleHeaderFormat      = "header-format"
+	RuleHeaderMaxLength   = "header-max-length"
+	RuleTypeCase          = "type-case"
+	RuleTypeEnum          = "type-enum"
+	RuleScopeRequired     = "scope-required"
+	RuleSubjectEmpty      = "subject-empty"
+	RuleSubjectCase       = "subject-case"
+	RuleSubjectSpace      = "subject-space"
+	RuleSubjectFullStop   = "subject-full-stop"
+	RuleTicketFormat      = "ticket-format"
+	RuleBodyLeadingBlank  = "body-leading-blank"
+	RuleBodyMaxLineLength = "body-max-line-length"
+	RuleFooterEmpty       = "footer-empty"
+	RuleBreakingFooter    = "breaking-footer"
+	RuleBreakingMarker    = "breaking-marker"
+)
+
+var ruleTable = []Rule{
+	{RuleHeaderEmpty, "commit message must not be empty", SeverityError},
+	{RuleHeaderFormat, "header must follow the configured style", SeverityError},
+	{RuleHeaderMaxLength, "header must not exceed rules.max_line_length", SeverityError},
+	{RuleTypeCase, "type must be lowercase", SeverityError},
+	{RuleTypeEnum, "type must be one of rules.types", SeverityError},
+	{RuleScopeRequired, "scope is required when rules.scope_required is set", SeverityError},
+	{RuleSubjectEmpty, "subject must not be empty", SeverityError},
+	{RuleSubjectCase, "subject must start lowercase when rules.lowercase_start is set", SeverityError},
+	{RuleSubjectSpace, "exactly one space must follow ':'", SeverityWarn},
+	{RuleSubjectFullStop, "subject must not end with a period", SeverityWarn},
+	{RuleTicketFormat, "ticket must look like ABC-123", SeverityError},
+	{RuleBodyLeadingBlank, "body must be separated from the header by a blank line", SeverityError},
+	{RuleBodyMaxLineLength, "body lines must not exceed rules.body_max_line_length", SeverityError},
+	{RuleFooterEmpty, "footers must have a value", SeverityError},
+	{RuleBreakingFooter, "'!' needs a BREAKING CHANGE footer when rules.breaking_footer_required is set", SeverityError},
+	{RuleBreakingMarker, "BREAKING CHANGE footer needs '!' when rules.breaking_marker_required is set", SeverityError},
+}
+
+// Rules returns every known rule, sorted by ID.
+func Rules() []Rule {
+	out := append([]Rule(nil), ruleTable...)
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// LookupRule finds a rule by ID.
+func LookupRule(id string) (Rule, bool) {
+	for _, r := range ruleTable {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Diagnostic is a single finding produced by a rule.
+type Diagnostic struct {
+	Rule     string
+	Severity Severity
+	Message  string
+	Span     Span
+}
+
+// String renders the diagnostic the way `bartle lint` prints it.
+func (d Diagnostic) String() string {
+	var sb strings.Builder
+	sb.WriteString(" - ")
+	if d.Span.StartLine > 1 {
+		fmt.Fprintf(&sb, "line %d: ", d.Span.StartLine)
+	}
+	sb.WriteString(d.Message)
+	fmt.Fprintf(&sb, " [%s]", d.Rule)
+	return sb.String()
+}
+
+func diag(rule string, span Span, format string, args ...any) Diagnostic {
+	return Diagnostic{Rule: rule, Message: fmt.Sprintf(format, args...), Span: span}
+}
+
+// applySeverities resolves each diagnostic's severity from rules.severity
+// (falling back to the rule default) and drops the ones turned off.
+func applySeverities(diags []Diagnostic, overrides map[string]string) []Diagnostic {
+	var out []Diagnostic
+	for _, d := range diags {
+		sev := SeverityError
+		if r, ok := LookupRule(d.Rule); ok {
+			sev = r.Default
+		}
+		if o, ok := overrides[d.Rule]; ok {
+			sev = Severity(strings.ToLower(o))
+		}
+		if sev == SeverityOff {
+			continue
+		}
+		d.Severity = sev
+		out = append(out, d)
+	}
+	return out
+}
+
+// CheckConfig reports configuration that only the linter can judge: an
+// unregistered style, or rules.severity entries naming rules that don't exist.
+func CheckConfig(cfg config.Config) error {
+	if problems := ConfigProblems(cfg); len(problems) > 0 {
+		return fmt.Errorf("%w: %v", config.ErrConfigMalformed, problems[0])
+	}
+	return nil
+}
+
+// ConfigProblems is CheckConfig reporting every problem instead of the first.
+func ConfigProblems(cfg config.Config) []config.Problem {
+	var out []config.Problem
+	if _, ok := LookupStyle(cfg.Style); !ok {
+		out = append(out, config.Problem{
+			Field:   "style",
+			Message: fmt.Sprintf("unknown style %q (available: %s)", cfg.Style, strings.Join(StyleNames(), ", ")),
+		})
+	}
+
+	var unknown []string
+	for id := range cfg.Rules.Severity {
+		if _, ok := LookupRule(id); !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+	for _, id := range unknown {
+		out = append(out, config.Problem{Field: "rules.severity." + id, Message: "unknown rule"})
+	}
+	return out
+}
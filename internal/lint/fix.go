@@ -0,0 +1,84 @@
+package lint
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+)
+
+// Fix describes one automatic repair applied to a message.
+type Fix struct {
+	Rule        string // rule the fix addresses, empty for pure cleanups
+	Description string
+}
+
+// FixResult is the repaired message plus what was changed.
+type FixResult struct {
+	Message string
+	Applied []Fix
+}
+
+// Changed reports whether any fix was applied.
+func (r FixResult) Changed() bool { return len(r.Applied) > 0 }
+
+// FixMessage applies every safe, mechanical repair it knows about. It never
+// changes the meaning of the message, only its formatting.
+func FixMessage(msg string, cfg config.Config) FixResult {
+	res := FixResult{Message: msg}
+
+	if strings.Contains(res.Message, "\r") {
+		res.Message = strings.ReplaceAll(res.Message, "\r\n", "\n")
+		res.Message = strings.ReplaceAll(res.Message, "\r", "")
+		res.Applied = append(res.Applied, Fix{Description: "normalized CRLF line endings"})
+	}
+
+	lines := strings.Split(res.Message, "\n")
+	trimmed := false
+	for i, l := range lines {
+		if t := strings.TrimRight(l, " \t"); t != l {
+			lines[i] = t
+			trimmed = true
+		}
+	}
+	if trimmed {
+		res.Applied = append(res.Applied, Fix{Description: "removed trailing whitespace"})
+	}
+
+	m := ParseMessage(strings.Join(lines, "\n"))
+	if style, ok := LookupStyle(cfg.Style); ok && m.Header != "" {
+		idx := m.HeaderSpan.StartLine - 1
+		header, fixes := style.FixHeader(m.Header, cfg.Rules)
+		if len(fixes) > 0 {
+			lines[idx] = strings.Replace(lines[idx], m.Header, header, 1)
+			res.Applied = append(res.Applied, fixes...)
+		}
+	}
+	if m.Header != "" && !m.BlankAfterHeader {
+		at := m.HeaderSpan.StartLine // index of the line after the header
+		lines = append(lines[:at], append([]string{""}, lines[at:]...)...)
+		res.Applied = append(res.Applied, Fix{Rule: RuleBodyLeadingBlank, Description: "inserted blank line after header"})
+	}
+
+	res.Message = strings.Join(lines, "\n")
+	return res
+}
+
+func fixSubject(subject string, lowercaseStart bool) (string, []Fix) {
+	var fixes []Fix
+
+	if hasFullStop(subject) {
+		subject = strings.TrimSuffix(subject, ".")
+		fixes = append(fixes, Fix{Rule: RuleSubjectFullStop, Description: "removed trailing period from subject"})
+	}
+
+	if lowercaseStart {
+		if r, size := utf8.DecodeRuneInString(subject); unicode.IsUpper(r) {
+			subject = string(unicode.ToLower(r)) + subject[size:]
+			fixes = append(fixes, Fix{Rule: RuleSubjectCase, Description: "lowercased first letter of subject"})
+		}
+	}
+
+	return subject, fixes
+}
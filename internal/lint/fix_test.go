@@ -0,0 +1,44 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+)
+
+func TestFixMessage(t *testing.T) {
+	lower := config.Default()
+	lower.Rules.LowercaseStart = true
+
+	jira := config.Default()
+	jira.Style = "jira"
+
+	tests := []struct {
+		name      string
+		cfg       config.Config
+		msg       string
+		want      string
+		wantFixes int
+	}{
+		{name: "already clean", cfg: config.Default(), msg: "feat(ui): add dropdown", want: "feat(ui): add dropdown"},
+		{name: "type case and spacing", cfg: config.Default(), msg: "FEAT(ui):add dropdown", want: "feat(ui): add dropdown", wantFixes: 2},
+		{name: "subject case only when configured", cfg: config.Default(), msg: "feat(ui): Add dropdown", want: "feat(ui): Add dropdown"},
+		{name: "subject case", cfg: lower, msg: "feat(ui)!: Add dropdown.", want: "feat(ui)!: add dropdown", wantFixes: 2},
+		{name: "ellipsis kept", cfg: config.Default(), msg: "feat: wait for it...", want: "feat: wait for it..."},
+		{name: "CRLF and blank line", cfg: config.Default(), msg: "fix(api): x\r\nbody\r\n", want: "fix(api): x\n\nbody\n", wantFixes: 2},
+		{name: "jira spacing", cfg: jira, msg: "ABC-123:  do the thing.", want: "ABC-123: do the thing", wantFixes: 2},
+		{name: "unparseable header untouched", cfg: config.Default(), msg: "just words", want: "just words"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := FixMessage(tt.msg, tt.cfg)
+			if res.Message != tt.want {
+				t.Fatalf("message: want %q, got %q", tt.want, res.Message)
+			}
+			if len(res.Applied) != tt.wantFixes {
+				t.Fatalf("fixes: want %d, got %d (%+v)", tt.wantFixes, len(res.Applied), res.Applied)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/branch"
+	"github.com/RyanTalbot/bartle/internal/config"
+)
+
+func init() { RegisterStyle(jiraStyle{}) }
+
+// jiraStyle expects a ticket key prefix: ABC-123: summary.
+type jiraStyle struct{}
+
+func (jiraStyle) Name() string { return "jira" }
+
+func (jiraStyle) Description() string {
+	return "JIRA ticket prefix: ABC-123: summary (rules.pattern tightens the format)"
+}
+
+func (jiraStyle) Example(config.Rules) string { return "ABC-123: add pagination" }
+
+func (jiraStyle) Validate(m Message, rules config.Rules) []Diagnostic {
+	return validateJIRA(m, rules)
+}
+
+func (jiraStyle) FixHeader(header string, _ config.Rules) (string, []Fix) {
+	return fixJIRAHeader(header)
+}
+
+func (jiraStyle) Prefill(info branch.Info, _ config.Rules) string {
+	if !looksLikeTicket(info.Ticket) {
+		return ""
+	}
+	return info.Ticket + ": "
+}
+
+func validateJIRA(m Message, rules config.Rules) []Diagnostic {
+	var out []Diagnostic
+	line := m.Header
+
+	colon := strings.Index(line, ":")
+	if colon <= 0 {
+		out = append(out, diag(RuleHeaderFormat, m.HeaderSpan, "missing ':' separator (e.g., ABC-123: summary)"))
+		return out
+	}
+
+	prefix := strings.TrimSpace(line[:colon])
+	subject := strings.TrimSpace(line[colon+1:])
+
+	if subject == "" {
+		out = append(out, diag(RuleSubjectEmpty, m.HeaderRange(colon, len(line)), "empty subject after ':'"))
+	}
+
+	if subject != "" && line[colon+1:] != " "+subject {
+		out = append(out, diag(RuleSubjectSpace, m.HeaderRange(colon, colon+1), "expected exactly one space after ':'"))
+	}
+
+	out = append(out, validateFullStop(m, subject)...)
+
+	if !looksLikeTicket(prefix) {
+		out = append(out, diag(RuleTicketFormat, m.HeaderRange(0, colon), "prefix %q doesn't look like a ticket (e.g., ABC-123)", prefix))
+	}
+
+	// rules.pattern is optional for JIRA; when present it tightens the format.
+	if rules.Pattern != "" {
+		_, ok, err := MatchPattern(rules.Pattern, line)
+		switch {
+		case err != nil:
+			out = append(out, diag(RuleHeaderFormat, m.HeaderSpan, "%v", err))
+		case !ok:
+			out = append(out, diag(RuleHeaderFormat, m.HeaderSpan, "header does not match rules.pattern %s", rules.Pattern))
+		}
+	}
+
+	out = append(out, validateHeaderLength(m, rules)...)
+
+	return out
+}
+
+func fixJIRAHeader(header string) (string, []Fix) {
+	colon := strings.Index(header, ":")
+	if colon <= 0 {
+		return header, nil
+	}
+	prefix := strings.TrimSpace(header[:colon])
+	rest := header[colon+1:]
+	subject := strings.TrimSpace(rest)
+	if subject == "" {
+		return header, nil
+	}
+
+	var fixes []Fix
+	if rest != " "+subject {
+		fixes = append(fixes, Fix{Rule: RuleSubjectSpace, Description: "put exactly one space after ':'"})
+	}
+	subject, subjectFixes := fixSubject(subject, false)
+	fixes = append(fixes, subjectFixes...)
+
+	return prefix + ": " + subject, fixes
+}
+
+func looksLikeTicket(s string) bool {
+	if len(s) < 5 {
+		return false
+	}
+	dash := strings.Index(s, "-")
+	if dash < 2 {
+		return false
+	}
+	left, right := s[:dash], s[dash+1:]
+	if left == "" || right == "" {
+		return false
+	}
+	for _, r := range left {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	for _, r := range right {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
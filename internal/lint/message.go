@@ -0,0 +1,234 @@
+package lint
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Span locates a piece of a commit message. Lines and columns are 1-based,
+// columns count runes, and End is exclusive (points just past the last rune).
+type Span struct {
+	StartLine int
+	StartCol  int
+	EndLine   int
+	EndCol    int
+}
+
+// Paragraph is a block of consecutive non-blank body lines.
+type Paragraph struct {
+	Text string
+	Span Span
+}
+
+// Footer is a single trailer such as "Refs: #123" or "BREAKING CHANGE: ...".
+// Values may continue over several lines; continuation lines are kept as-is.
+type Footer struct {
+	Token     string
+	Separator string // ": " or " #"
+	Value     string
+	Span      Span
+}
+
+// Message is a commit message split into header, body and footers following
+// the Conventional Commits layout:
+//
+//	<header>
+//	<blank line>
+//	<body paragraphs separated by blank lines>
+//	<blank line>
+//	<footers>
+type Message struct {
+	Raw        string
+	Header     string
+	HeaderSpan Span
+	// BlankAfterHeader reports whether the header is followed by an empty line
+	// (or nothing at all), as git and the spec expect.
+	BlankAfterHeader bool
+	Body             []Paragraph
+	Footers          []Footer
+}
+
+// ParseMessage splits a raw commit message into its parts. It never fails:
+// an empty message yields an empty header.
+func ParseMessage(raw string) Message {
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	raw = strings.ReplaceAll(raw, "\r", "")
+	lines := strings.Split(strings.TrimRight(raw, "\n"), "\n")
+
+	m := Message{Raw: raw}
+
+	// Leading blank lines are ignored by git, so skip them too.
+	first := 0
+	for first < len(lines) && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+	if first >= len(lines) {
+		m.BlankAfterHeader = true
+		return m
+	}
+
+	m.Header = strings.TrimSpace(lines[first])
+	lead := utf8.RuneCountInString(lines[first][:strings.Index(lines[first], m.Header)])
+	m.HeaderSpan = Span{
+		StartLine: first + 1,
+		StartCol:  lead + 1,
+		EndLine:   first + 1,
+		EndCol:    lead + utf8.RuneCountInString(m.Header) + 1,
+	}
+	m.BlankAfterHeader = first+1 >= len(lines) || strings.TrimSpace(lines[first+1]) == ""
+
+	paragraphs := splitParagraphs(lines, first+1)
+	if n := len(paragraphs); n > 0 && isFooterLine(lines[paragraphs[n-1].start]) {
+		last := paragraphs[n-1]
+		m.Footers = parseFooters(lines, last.start, last.end)
+		paragraphs = paragraphs[:n-1]
+	}
+
+	for _, p := range paragraphs {
+		m.Body = append(m.Body, Paragraph{
+			Text: strings.Join(lines[p.start:p.end], "\n"),
+			Span: blockSpan(lines, p.start, p.end),
+		})
+	}
+
+	return m
+}
+
+// HeaderRange returns the span of Header[start:end] (byte offsets).
+func (m Message) HeaderRange(start, end int) Span {
+	col := m.HeaderSpan.StartCol
+	return Span{
+		StartLine: m.HeaderSpan.StartLine,
+		StartCol:  col + utf8.RuneCountInString(m.Header[:start]),
+		EndLine:   m.HeaderSpan.StartLine,
+		EndCol:    col + utf8.RuneCountInString(m.Header[:end]),
+	}
+}
+
+// BodyLines returns every body line together with its 1-based line number.
+func (m Message) BodyLines() []NumberedLine {
+	var out []NumberedLine
+	for _, p := range m.Body {
+		for i, l := range strings.Split(p.Text, "\n") {
+			out = append(out, NumberedLine{Number: p.Span.StartLine + i, Text: l})
+		}
+	}
+	return out
+}
+
+// BreakingFooter returns the first BREAKING CHANGE (or BREAKING-CHANGE) footer.
+func (m Message) BreakingFooter() (Footer, bool) {
+	for _, f := range m.Footers {
+		if IsBreakingToken(f.Token) {
+			return f, true
+		}
+	}
+	return Footer{}, false
+}
+
+// IsBreakingToken reports whether a footer token announces a breaking change.
+// The spec makes BREAKING-CHANGE a synonym of BREAKING CHANGE.
+func IsBreakingToken(token string) bool {
+	return token == "BREAKING CHANGE" || token == "BREAKING-CHANGE"
+}
+
+// NumberedLine is a single line of a message with its 1-based line number.
+type NumberedLine struct {
+	Number int
+	Text   string
+}
+
+type block struct{ start, end int } // [start, end) line indexes
+
+func splitParagraphs(lines []string, from int) []block {
+	var out []block
+	start := -1
+	for i := from; i < len(lines); i++ {
+		blank := strings.TrimSpace(lines[i]) == ""
+		switch {
+		case !blank && start < 0:
+			start = i
+		case blank && start >= 0:
+			out = append(out, block{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		out = append(out, block{start, len(lines)})
+	}
+	return out
+}
+
+// isFooterLine reports whether line starts a footer: "Token: value" or
+// "Token #value", where Token has no spaces ("-" stands in for whitespace)
+// except for the special "BREAKING CHANGE" token.
+func isFooterLine(line string) bool {
+	_, _, _, ok := splitFooter(line)
+	return ok
+}
+
+func splitFooter(line string) (token, sep, value string, ok bool) {
+	if strings.HasPrefix(line, "BREAKING CHANGE: ") {
+		return "BREAKING CHANGE", ": ", line[len("BREAKING CHANGE: "):], true
+	}
+
+	for i, r := range line {
+		switch {
+		case r == ':' && strings.HasPrefix(line[i:], ": ") && i > 0:
+			return line[:i], ": ", line[i+2:], true
+		case r == ' ' && strings.HasPrefix(line[i:], " #") && i > 0:
+			return line[:i], " #", line[i+2:], true
+		case isTokenRune(r):
+			continue
+		}
+		return "", "", "", false
+	}
+	return "", "", "", false
+}
+
+func isTokenRune(r rune) bool {
+	return r == '-' || r == '_' ||
+		(r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func parseFooters(lines []string, start, end int) []Footer {
+	var out []Footer
+	for i := start; i < end; i++ {
+		token, sep, value, ok := splitFooter(lines[i])
+		if !ok && len(out) > 0 {
+			// Continuation of the previous footer's value.
+			f := &out[len(out)-1]
+			f.Value += "\n" + lines[i]
+			f.Span = blockSpan(lines, f.Span.StartLine-1, i+1)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		out = append(out, Footer{
+			Token:     token,
+			Separator: sep,
+			Value:     value,
+			Span:      lineSpan(i, lines[i]),
+		})
+	}
+	return out
+}
+
+func lineSpan(idx int, line string) Span {
+	return Span{
+		StartLine: idx + 1,
+		StartCol:  1,
+		EndLine:   idx + 1,
+		EndCol:    utf8.RuneCountInString(line) + 1,
+	}
+}
+
+func blockSpan(lines []string, start, end int) Span {
+	return Span{
+		StartLine: start + 1,
+		StartCol:  1,
+		EndLine:   end,
+		EndCol:    utf8.RuneCountInString(lines[end-1]) + 1,
+	}
+}
@@ -0,0 +1,109 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		wantHeader  string
+		wantBlank   bool
+		wantBody    []string
+		wantFooters []Footer
+	}{
+		{
+			name:       "header only",
+			raw:        "feat(ui): add dropdown",
+			wantHeader: "feat(ui): add dropdown",
+			wantBlank:  true,
+		},
+		{
+			name:       "header with body paragraphs",
+			raw:        "fix: handle nil\n\nfirst paragraph\ncontinues here\n\nsecond paragraph\n",
+			wantHeader: "fix: handle nil",
+			wantBlank:  true,
+			wantBody:   []string{"first paragraph\ncontinues here", "second paragraph"},
+		},
+		{
+			name:       "body and footers with CRLF",
+			raw:        "feat: x\r\n\r\nbody\r\n\r\nRefs #123\r\nReviewed-by: Jane\r\n",
+			wantHeader: "feat: x",
+			wantBlank:  true,
+			wantBody:   []string{"body"},
+			wantFooters: []Footer{
+				{Token: "Refs", Separator: " #", Value: "123", Span: Span{5, 1, 5, 10}},
+				{Token: "Reviewed-by", Separator: ": ", Value: "Jane", Span: Span{6, 1, 6, 18}},
+			},
+		},
+		{
+			name:       "breaking change footer with continuation",
+			raw:        "feat!: drop v1\n\nBREAKING CHANGE: v1 endpoints removed\nuse /v2 instead",
+			wantHeader: "feat!: drop v1",
+			wantBlank:  true,
+			wantFooters: []Footer{
+				{Token: "BREAKING CHANGE", Separator: ": ", Value: "v1 endpoints removed\nuse /v2 instead", Span: Span{3, 1, 4, 16}},
+			},
+		},
+		{
+			name:       "missing blank line after header",
+			raw:        "feat: x\nbody right away",
+			wantHeader: "feat: x",
+			wantBlank:  false,
+			wantBody:   []string{"body right away"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ParseMessage(tt.raw)
+
+			if m.Header != tt.wantHeader {
+				t.Fatalf("header: want %q, got %q", tt.wantHeader, m.Header)
+			}
+			if m.BlankAfterHeader != tt.wantBlank {
+				t.Fatalf("blank after header: want %v, got %v", tt.wantBlank, m.BlankAfterHeader)
+			}
+
+			var body []string
+			for _, p := range m.Body {
+				body = append(body, p.Text)
+			}
+			if !reflect.DeepEqual(body, tt.wantBody) {
+				t.Fatalf("body: want %q, got %q", tt.wantBody, body)
+			}
+			if !reflect.DeepEqual(m.Footers, tt.wantFooters) {
+				t.Fatalf("footers mismatch\nwant: %+v\ngot:  %+v", tt.wantFooters, m.Footers)
+			}
+		})
+	}
+}
+
+func TestParseConventionalBreaking(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        string
+		wantMarker bool
+		wantBreak  bool
+		wantNote   string
+	}{
+		{name: "not breaking", raw: "feat: x"},
+		{name: "marker only", raw: "feat(api)!: x", wantMarker: true, wantBreak: true},
+		{name: "footer only", raw: "feat: x\n\nBREAKING CHANGE: gone", wantBreak: true, wantNote: "gone"},
+		{name: "hyphenated footer", raw: "feat!: x\n\nBREAKING-CHANGE: gone", wantMarker: true, wantBreak: true, wantNote: "gone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := ParseConventional(ParseMessage(tt.raw))
+			if !ok {
+				t.Fatalf("ParseConventional(%q) failed", tt.raw)
+			}
+			if p.BreakingMarker != tt.wantMarker || p.Breaking != tt.wantBreak || p.BreakingNote != tt.wantNote {
+				t.Fatalf("got marker=%v breaking=%v note=%q", p.BreakingMarker, p.Breaking, p.BreakingNote)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/branch"
+	"github.com/RyanTalbot/bartle/internal/config"
+)
+
+// Style is a commit message convention. Styles register themselves from an
+// init function so ValidateMessage, FixMessage and `bartle styles list` pick
+// them up without further wiring.
+type Style interface {
+	// Name is the value users put in the style key of .bartle.yaml.
+	Name() string
+	Description() string
+	// Example returns a header that satisfies the style under rules.
+	Example(rules config.Rules) string
+	// Validate checks everything style-specific; body and footer checks
+	// shared by all styles are applied by ValidateMessage.
+	Validate(m Message, rules config.Rules) []Diagnostic
+	// FixHeader returns a mechanically repaired header and what changed.
+	FixHeader(header string, rules config.Rules) (string, []Fix)
+	// Prefill returns the start of a message built from what the branch
+	// name gave away, or "" when the style can't use any of it.
+	Prefill(info branch.Info, rules config.Rules) string
+}
+
+// DefaultStyle is used when .bartle.yaml doesn't set a style.
+const DefaultStyle = "conventional"
+
+var styles = map[string]Style{}
+
+// RegisterStyle makes a style available by name. It panics on duplicates,
+// which can only happen through a programming error.
+func RegisterStyle(s Style) {
+	name := strings.ToLower(s.Name())
+	if _, dup := styles[name]; dup {
+		panic(fmt.Sprintf("lint: style %q registered twice", name))
+	}
+	styles[name] = s
+}
+
+// LookupStyle finds a registered style by name, case-insensitively.
+// An empty name selects DefaultStyle.
+func LookupStyle(name string) (Style, bool) {
+	if name == "" {
+		name = DefaultStyle
+	}
+	s, ok := styles[strings.ToLower(name)]
+	return s, ok
+}
+
+// Styles returns every registered style, sorted by name.
+func Styles() []Style {
+	out := make([]Style, 0, len(styles))
+	for _, s := range styles {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out
+}
+
+// StyleNames returns the names of every registered style, sorted.
+func StyleNames() []string {
+	var names []string
+	for _, s := range Styles() {
+		names = append(names, s.Name())
+	}
+	return names
+}
@@ -0,0 +1,38 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/RyanTalbot/bartle/internal/branch"
+	"github.com/RyanTalbot/bartle/internal/config"
+)
+
+func TestStylePrefill(t *testing.T) {
+	rules := config.Default().Rules
+
+	tests := []struct {
+		style string
+		info  branch.Info
+		want  string
+	}{
+		{"conventional", branch.Info{Type: "feat", Ticket: "ABC-123"}, "feat: \n\nRefs: ABC-123"},
+		{"conventional", branch.Info{Type: "fix", Scope: "ui"}, "fix(ui): "},
+		{"conventional", branch.Info{Type: "feature", Ticket: "ABC-123"}, "\n\nRefs: ABC-123"},
+		{"conventional", branch.Info{Ticket: "abc-123"}, ""},
+		{"jira", branch.Info{Type: "feat", Ticket: "ABC-123"}, "ABC-123: "},
+		{"jira", branch.Info{Type: "feat"}, ""},
+		{"custom", branch.Info{Ticket: "ABC-123"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style+"/"+tt.want, func(t *testing.T) {
+			style, ok := LookupStyle(tt.style)
+			if !ok {
+				t.Fatalf("style %q not registered", tt.style)
+			}
+			if got := style.Prefill(tt.info, rules); got != tt.want {
+				t.Fatalf("Prefill(%+v) = %q, want %q", tt.info, got, tt.want)
+			}
+		})
+	}
+}
@@ -9,145 +9,115 @@ import (
 )
 
 type Result struct {
-	Valid  bool
-	Errors []string
+	Valid       bool
+	Diagnostics []Diagnostic
 }
 
-func ValidateMessage(msg string, cfg config.Config) Result {
-	var res Result
-
-	// TODO: handle body/footer rules.
-	firstLine := strings.Split(msg, "\n")[0]
-	firstLine = strings.ReplaceAll(firstLine, "\r", "") // normalize CRLF
-	firstLine = strings.TrimSpace(firstLine)
-	if firstLine == "" {
-		res.Errors = append(res.Errors, Errorf("empty commit message"))
-		return finish(res)
-	}
+// Errors returns the diagnostics that fail the message.
+func (r Result) Errors() []Diagnostic { return r.filter(SeverityError) }
 
-	switch strings.ToLower(cfg.Style) {
-	case "conventional", "":
-		res = validateConventional(firstLine, cfg.Rules)
-	case "jira":
-		res = validateJIRA(firstLine, cfg.Rules)
-	default:
-		res = validateConventional(firstLine, cfg.Rules)
-	}
+// Warnings returns the diagnostics that are reported but don't fail the message.
+func (r Result) Warnings() []Diagnostic { return r.filter(SeverityWarn) }
 
-	return finish(res)
+func (r Result) filter(sev Severity) []Diagnostic {
+	var out []Diagnostic
+	for _, d := range r.Diagnostics {
+		if d.Severity == sev {
+			out = append(out, d)
+		}
+	}
+	return out
 }
 
-func finish(r Result) Result {
-	r.Valid = len(r.Errors) == 0
-	return r
-}
+func ValidateMessage(msg string, cfg config.Config) Result {
+	var diags []Diagnostic
 
-func validateConventional(line string, rules config.Rules) Result {
-	var out Result
+	m := ParseMessage(msg)
+	if m.Header == "" {
+		diags = append(diags, diag(RuleHeaderEmpty, m.HeaderSpan, "empty commit message"))
+		return finish(diags, cfg.Rules)
+	}
 
-	parsed, ok := ParseConventionalLine(line)
+	style, ok := LookupStyle(cfg.Style)
 	if !ok {
-		colonIdx := strings.Index(line, ":")
-		if colonIdx < 0 {
-			out.Errors = append(out.Errors, Errorf("missing ':' separator (e.g., %s)", FormatExample(rules.ScopeRequired)))
-			return finish(out)
-		}
+		// CheckConfig rejects this up front; never fall back silently.
+		diags = append(diags, diag(RuleHeaderFormat, m.HeaderSpan, "unknown style %q (available: %s)", cfg.Style, strings.Join(StyleNames(), ", ")))
+		return finish(diags, cfg.Rules)
+	}
 
-		subject := strings.TrimSpace(line[colonIdx+1:])
-		if subject == "" {
-			out.Errors = append(out.Errors, Errorf("empty subject after ':'"))
-		}
+	diags = style.Validate(m, cfg.Rules)
+	diags = append(diags, validateBody(m, cfg.Rules)...)
 
-		hasOpen := strings.Contains(line, "(")
-		hasClose := strings.Contains(line, ")")
-		if hasOpen && !hasClose {
-			out.Errors = append(out.Errors, Errorf("unclosed scope '(' — expected ')': e.g., %s", FormatExample(true)))
-		}
-		if rules.ScopeRequired && !hasOpen {
-			out.Errors = append(out.Errors, Errorf("missing scope (e.g., type(scope): subject)"))
-		}
-
-		if len(out.Errors) == 0 {
-			out.Errors = append(out.Errors, Errorf("not conventional format (e.g., %s)", FormatExample(rules.ScopeRequired)))
-		}
-		return finish(out)
-	}
+	return finish(diags, cfg.Rules)
+}
 
-	if parsed.Type != strings.ToLower(parsed.Type) {
-		out.Errors = append(out.Errors, Errorf("type must be lowercase (got %q)", parsed.Type))
-	}
+func finish(diags []Diagnostic, rules config.Rules) Result {
+	r := Result{Diagnostics: applySeverities(diags, rules.Severity)}
+	r.Valid = len(r.Errors()) == 0
+	return r
+}
 
-	if !inStringSet(rules.Types, parsed.Type) {
-		out.Errors = append(out.Errors, Errorf("type %q not allowed (choose one of: %s)", parsed.Type, strings.Join(rules.Types, ", ")))
-	}
+// validateBreaking checks that the `!` marker and the BREAKING CHANGE footer
+// tell the same story, as far as the configured rules require.
+func validateBreaking(m Message, parsed Parsed, rules config.Rules) []Diagnostic {
+	var out []Diagnostic
 
-	if rules.ScopeRequired && parsed.Scope == "" {
-		out.Errors = append(out.Errors, Errorf("scope required (e.g., %s)", "type(scope): subject"))
-	}
+	footer, hasFooter := m.BreakingFooter()
 
-	if rules.MaxLineLength > 0 && utf8.RuneCountInString(line) > rules.MaxLineLength {
-		out.Errors = append(out.Errors, Errorf("first line too long (%d > %d)",
-			utf8.RuneCountInString(line), rules.MaxLineLength))
+	if rules.BreakingFooterRequired && parsed.BreakingMarker && !hasFooter {
+		out = append(out, diag(RuleBreakingFooter, m.HeaderSpan, "breaking change marked with '!' needs a BREAKING CHANGE footer explaining the migration"))
 	}
 
-	if rules.LowercaseStart && len(parsed.Subject) > 0 && isUpper(rune(parsed.Subject[0])) {
-		out.Errors = append(out.Errors, Errorf("subject should start lowercase"))
+	if rules.BreakingMarkerRequired && hasFooter && !parsed.BreakingMarker {
+		out = append(out, diag(RuleBreakingMarker, footer.Span, "BREAKING CHANGE footer present but header is missing '!' (e.g., %s)", "type(scope)!: subject"))
 	}
 
-	return finish(out)
+	return out
 }
 
-func validateJIRA(line string, rules config.Rules) Result {
-	var out Result
-
-	colon := strings.Index(line, ":")
-	if colon <= 0 {
-		out.Errors = append(out.Errors, Errorf("missing ':' separator (e.g., ABC-123: summary)"))
-		return finish(out)
+func validateFullStop(m Message, subject string) []Diagnostic {
+	if !hasFullStop(subject) {
+		return nil
 	}
+	end := len(m.Header)
+	return []Diagnostic{diag(RuleSubjectFullStop, m.HeaderRange(end-1, end), "subject should not end with a period")}
+}
 
-	prefix := strings.TrimSpace(line[:colon])
-	subject := strings.TrimSpace(line[colon+1:])
-
-	if subject == "" {
-		out.Errors = append(out.Errors, Errorf("empty subject after ':'"))
+func validateHeaderLength(m Message, rules config.Rules) []Diagnostic {
+	n := utf8.RuneCountInString(m.Header)
+	if rules.MaxLineLength <= 0 || n <= rules.MaxLineLength {
+		return nil
 	}
+	span := m.HeaderSpan
+	span.StartCol += rules.MaxLineLength
+	return []Diagnostic{diag(RuleHeaderMaxLength, span, "first line too long (%d > %d)", n, rules.MaxLineLength)}
+}
 
-	if !looksLikeTicket(prefix) {
-		out.Errors = append(out.Errors, Errorf("prefix %q doesn't look like a ticket (e.g., ABC-123)", prefix))
-	}
+// validateBody applies the style-independent checks on everything below the header.
+func validateBody(m Message, rules config.Rules) []Diagnostic {
+	var out []Diagnostic
 
-	if rules.MaxLineLength > 0 && utf8.RuneCountInString(line) > rules.MaxLineLength {
-		out.Errors = append(out.Errors, Errorf("first line too long (%d > %d)",
-			utf8.RuneCountInString(line), rules.MaxLineLength))
+	if !m.BlankAfterHeader {
+		next := m.HeaderSpan.StartLine + 1
+		out = append(out, diag(RuleBodyLeadingBlank, Span{next, 1, next, 1}, "body must be separated from the header by a blank line"))
 	}
 
-	return finish(out)
-}
-
-func looksLikeTicket(s string) bool {
-	if len(s) < 5 {
-		return false
-	}
-	dash := strings.Index(s, "-")
-	if dash < 2 {
-		return false
-	}
-	left, right := s[:dash], s[dash+1:]
-	if left == "" || right == "" {
-		return false
-	}
-	for _, r := range left {
-		if r < 'A' || r > 'Z' {
-			return false
+	if rules.BodyMaxLineLength > 0 {
+		for _, l := range m.BodyLines() {
+			if n := utf8.RuneCountInString(l.Text); n > rules.BodyMaxLineLength {
+				span := Span{l.Number, rules.BodyMaxLineLength + 1, l.Number, n + 1}
+				out = append(out, diag(RuleBodyMaxLineLength, span, "body line too long (%d > %d)", n, rules.BodyMaxLineLength))
+			}
 		}
 	}
-	for _, r := range right {
-		if r < '0' || r > '9' {
-			return false
+
+	for _, f := range m.Footers {
+		if strings.TrimSpace(f.Value) == "" {
+			out = append(out, diag(RuleFooterEmpty, f.Span, "footer %q has an empty value", f.Token))
 		}
 	}
-	return true
+
+	return out
 }
 
 func inStringSet(list []string, v string) bool {
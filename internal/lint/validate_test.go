@@ -0,0 +1,57 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+)
+
+func TestValidateMessage(t *testing.T) {
+	custom := config.Default()
+	custom.Style = "custom"
+	custom.Rules.Pattern = `^(?P<ticket>[A-Z]+-\d+) (?P<type>[a-z]+): (?P<subject>.+)$`
+
+	breaking := config.Default()
+	breaking.Rules.ScopeRequired = false
+	breaking.Rules.BreakingFooterRequired = true
+	breaking.Rules.BreakingMarkerRequired = true
+
+	relaxed := config.Default()
+	relaxed.Rules.Severity = map[string]string{"type-enum": "warn", "scope-required": "off"}
+
+	unknown := config.Default()
+	unknown.Style = "nope"
+
+	tests := []struct {
+		name      string
+		cfg       config.Config
+		msg       string
+		wantValid bool
+	}{
+		{name: "conventional ok", cfg: config.Default(), msg: "feat(ui): add dropdown", wantValid: true},
+		{name: "conventional bad type", cfg: config.Default(), msg: "feature(ui): add dropdown"},
+		{name: "body needs blank line", cfg: config.Default(), msg: "feat(ui): add\nbody"},
+		{name: "type-enum downgraded to warning", cfg: relaxed, msg: "feature: add dropdown", wantValid: true},
+		{name: "unknown style never falls back", cfg: unknown, msg: "feat(ui): add dropdown"},
+		{name: "custom ok", cfg: custom, msg: "ABC-1 feat: add thing", wantValid: true},
+		{name: "custom type from group", cfg: custom, msg: "ABC-1 wip: add thing"},
+		{name: "custom no match", cfg: custom, msg: "feat: add thing"},
+		{name: "breaking agrees", cfg: breaking, msg: "feat!: drop v1\n\nBREAKING CHANGE: use v2", wantValid: true},
+		{name: "breaking marker without footer", cfg: breaking, msg: "feat!: drop v1"},
+		{name: "breaking footer without marker", cfg: breaking, msg: "feat: drop v1\n\nBREAKING CHANGE: use v2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := ValidateMessage(tt.msg, tt.cfg)
+			if res.Valid != tt.wantValid {
+				t.Fatalf("Valid = %v, want %v (diagnostics: %v)", res.Valid, tt.wantValid, res.Diagnostics)
+			}
+			for _, d := range res.Diagnostics {
+				if d.Severity == SeverityOff || d.Severity == "" {
+					t.Fatalf("diagnostic with unresolved severity: %+v", d)
+				}
+			}
+		})
+	}
+}
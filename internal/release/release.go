@@ -0,0 +1,126 @@
+package release
+
+import (
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/RyanTalbot/bartle/internal/lint"
+	"github.com/RyanTalbot/bartle/internal/semver"
+)
+
+// Change is a commit together with what bartle's parser made of it.
+type Change struct {
+	Commit git.Commit
+	Parsed lint.Parsed
+	// Conventional is false when the message couldn't be parsed; such
+	// commits never bump the version.
+	Conventional bool
+	Bump         semver.Bump
+}
+
+// Plan is the outcome of a next-version calculation.
+type Plan struct {
+	CurrentTag string // empty when no version tag exists yet
+	Current    semver.Version
+	Next       semver.Version
+	Bump       semver.Bump
+	Changes    []Change
+}
+
+// LatestTag returns the highest semver tag with prefix reachable from HEAD.
+func LatestTag(prefix string) (string, semver.Version, bool, error) {
+	return LatestTagFrom(prefix, "HEAD")
+}
+
+// LatestTagFrom returns the highest semver tag with prefix reachable from rev.
+func LatestTagFrom(prefix, rev string) (string, semver.Version, bool, error) {
+	tags, err := git.MergedTags(rev)
+	if err != nil {
+		return "", semver.Version{}, false, err
+	}
+	tag, v, found := highestTag(prefix, tags, nil)
+	return tag, v, found, nil
+}
+
+// PreviousTag returns the highest semver tag with prefix reachable from rev
+// but not on rev itself: where the release ending at rev starts.
+func PreviousTag(prefix, rev string) (string, bool, error) {
+	tags, err := git.MergedTags(rev)
+	if err != nil {
+		return "", false, err
+	}
+	at, err := git.TagsAt(rev)
+	if err != nil {
+		return "", false, err
+	}
+	skip := map[string]bool{}
+	for _, t := range at {
+		skip[t] = true
+	}
+	tag, _, found := highestTag(prefix, tags, skip)
+	return tag, found, nil
+}
+
+func highestTag(prefix string, tags []string, skip map[string]bool) (string, semver.Version, bool) {
+	var (
+		best    semver.Version
+		bestTag string
+		found   bool
+	)
+	for _, tag := range tags {
+		if skip[tag] || !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+		v, err := semver.Parse(strings.TrimPrefix(tag, prefix))
+		if err != nil {
+			continue // not a version tag
+		}
+		if !found || v.Compare(best) > 0 {
+			best, bestTag, found = v, tag, true
+		}
+	}
+	return bestTag, best, found
+}
+
+// Classify parses each commit message and decides its bump: breaking
+// changes are major, otherwise rel.Bumps decides by type.
+func Classify(commits []git.Commit, rel config.Release) []Change {
+	changes := make([]Change, 0, len(commits))
+	for _, c := range commits {
+		ch := Change{Commit: c, Bump: semver.BumpNone}
+		ch.Parsed, ch.Conventional = lint.ParseConventional(lint.ParseMessage(c.Message))
+		if ch.Conventional {
+			ch.Bump = bumpFor(ch.Parsed, rel)
+		}
+		changes = append(changes, ch)
+	}
+	return changes
+}
+
+func bumpFor(p lint.Parsed, rel config.Release) semver.Bump {
+	if p.Breaking {
+		return semver.BumpMajor
+	}
+	// Unlisted types fail to parse and don't bump.
+	b, err := semver.ParseBump(rel.Bumps[strings.ToLower(p.Type)])
+	if err != nil {
+		return semver.BumpNone
+	}
+	return b
+}
+
+// Next plans the release that follows current given the commits since it.
+func Next(currentTag string, current semver.Version, commits []git.Commit, rel config.Release) Plan {
+	plan := Plan{
+		CurrentTag: currentTag,
+		Current:    current,
+		Bump:       semver.BumpNone,
+		Changes:    Classify(commits, rel),
+	}
+	for _, ch := range plan.Changes {
+		plan.Bump = plan.Bump.Max(ch.Bump)
+	}
+	plan.Next = current.Apply(plan.Bump)
+	return plan
+}
@@ -0,0 +1,38 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/git"
+	"github.com/RyanTalbot/bartle/internal/semver"
+)
+
+func TestNext(t *testing.T) {
+	rel := config.Default().Release
+	base, _ := semver.Parse("1.4.2")
+
+	tests := []struct {
+		name     string
+		messages []string
+		want     string
+	}{
+		{name: "nothing releasable", messages: []string{"docs: readme", "not conventional"}, want: "1.4.2"},
+		{name: "fix", messages: []string{"fix(api): nil check", "chore: tidy"}, want: "1.4.3"},
+		{name: "feat beats fix", messages: []string{"fix: a", "feat(ui): b"}, want: "1.5.0"},
+		{name: "breaking marker", messages: []string{"feat!: drop v1"}, want: "2.0.0"},
+		{name: "breaking footer", messages: []string{"docs: x\n\nBREAKING CHANGE: config moved"}, want: "2.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commits []git.Commit
+			for _, m := range tt.messages {
+				commits = append(commits, git.Commit{SHA: "abc", Message: m})
+			}
+			if got := Next("v1.4.2", base, commits, rel).Next.String(); got != tt.want {
+				t.Fatalf("Next = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
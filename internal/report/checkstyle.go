@@ -0,0 +1,55 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/RyanTalbot/bartle/internal/lint"
+)
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle emits one <file> per commit message, named after the
+// message file or the commit.
+func writeCheckstyle(w io.Writer, entries []Entry) error {
+	doc := checkstyleReport{Version: "4.3"}
+	for _, e := range entries {
+		f := checkstyleFile{Name: e.Name()}
+		for _, d := range e.Result.Diagnostics {
+			span := spanOrStart(d.Span)
+			f.Errors = append(f.Errors, checkstyleError{
+				Line:     span.StartLine,
+				Column:   span.StartCol,
+				Severity: checkstyleSeverity(d.Severity),
+				Message:  d.Message,
+				Source:   "bartle." + d.Rule,
+			})
+		}
+		doc.Files = append(doc.Files, f)
+	}
+	return writeXML(w, doc)
+}
+
+func checkstyleSeverity(s lint.Severity) string {
+	if s == lint.SeverityWarn {
+		return "warning"
+	}
+	return "error"
+}
@@ -0,0 +1,51 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/lint"
+)
+
+// writeGitHub emits GitHub Actions workflow commands so diagnostics show up
+// as annotations on the pull request.
+// See https://docs.github.com/actions/using-workflows/workflow-commands-for-github-actions.
+func writeGitHub(w io.Writer, entries []Entry) error {
+	for _, e := range entries {
+		for _, d := range e.Result.Diagnostics {
+			level := "error"
+			if d.Severity == lint.SeverityWarn {
+				level = "warning"
+			}
+
+			props := []string{"title=" + escapeProperty("bartle: "+d.Rule)}
+			if e.Source != "" {
+				span := spanOrStart(d.Span)
+				props = append(props,
+					"file="+escapeProperty(e.Source),
+					fmt.Sprintf("line=%d", span.StartLine),
+					fmt.Sprintf("col=%d", span.StartCol),
+				)
+			}
+
+			msg := d.Message
+			if e.SHA != "" {
+				msg = fmt.Sprintf("%s: %s", e.Name(), d.Message)
+			}
+
+			if _, err := fmt.Fprintf(w, "::%s %s::%s\n", level, strings.Join(props, ","), escapeData(msg)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
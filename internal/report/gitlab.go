@@ -0,0 +1,69 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/RyanTalbot/bartle/internal/lint"
+)
+
+// GitLabReportFile is where GitLab CI jobs conventionally write the code
+// quality report (artifacts:reports:codequality).
+const GitLabReportFile = "gl-code-quality-report.json"
+
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+}
+
+// writeGitLab emits a GitLab code quality report.
+// See https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool.
+func writeGitLab(w io.Writer, entries []Entry) error {
+	issues := []gitlabIssue{}
+	for _, e := range entries {
+		path := e.path()
+
+		for _, d := range e.Result.Diagnostics {
+			desc := d.Message
+			if e.SHA != "" {
+				desc = e.Name() + ": " + d.Message
+			}
+
+			sum := sha256.Sum256([]byte(e.SHA + "\x00" + e.Source + "\x00" + d.Rule + "\x00" + d.Message))
+			severity := "major"
+			if d.Severity == lint.SeverityWarn {
+				severity = "minor"
+			}
+
+			issues = append(issues, gitlabIssue{
+				Description: desc,
+				CheckName:   d.Rule,
+				Fingerprint: hex.EncodeToString(sum[:16]),
+				Severity:    severity,
+				Location: gitlabLocation{
+					Path:  path,
+					Lines: gitlabLines{Begin: spanOrStart(d.Span).StartLine},
+				},
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type jsonReport struct {
+	Valid   bool        `json:"valid"`
+	Results []jsonEntry `json:"results"`
+}
+
+type jsonEntry struct {
+	SHA         string           `json:"sha,omitempty"`
+	Subject     string           `json:"subject,omitempty"`
+	Source      string           `json:"source,omitempty"`
+	Valid       bool             `json:"valid"`
+	Diagnostics []jsonDiagnostic `json:"diagnostics"`
+}
+
+type jsonDiagnostic struct {
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+}
+
+func writeJSON(w io.Writer, entries []Entry) error {
+	out := jsonReport{Valid: allValid(entries), Results: []jsonEntry{}}
+	for _, e := range entries {
+		je := jsonEntry{
+			SHA:         e.SHA,
+			Subject:     e.Subject,
+			Source:      e.Source,
+			Valid:       e.Result.Valid,
+			Diagnostics: []jsonDiagnostic{},
+		}
+		for _, d := range e.Result.Diagnostics {
+			je.Diagnostics = append(je.Diagnostics, jsonDiagnostic{
+				Rule:      d.Rule,
+				Severity:  string(d.Severity),
+				Message:   d.Message,
+				Line:      d.Span.StartLine,
+				Column:    d.Span.StartCol,
+				EndLine:   d.Span.EndLine,
+				EndColumn: d.Span.EndCol,
+			})
+		}
+		out.Results = append(out.Results, je)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit emits one test case per commit message; errors fail the case
+// and warnings go to system-out.
+func writeJUnit(w io.Writer, entries []Entry) error {
+	suite := junitSuite{Name: "bartle"}
+	for _, e := range entries {
+		tc := junitCase{Name: e.Name(), ClassName: "bartle.lint"}
+
+		if errs := e.Result.Errors(); len(errs) > 0 {
+			var lines []string
+			for _, d := range errs {
+				lines = append(lines, fmt.Sprintf("%s [%s] line %d", d.Message, d.Rule, spanOrStart(d.Span).StartLine))
+			}
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d lint error(s)", len(errs)),
+				Type:    errs[0].Rule,
+				Text:    strings.Join(lines, "\n"),
+			}
+			suite.Failures++
+		}
+
+		var warnings []string
+		for _, d := range e.Result.Warnings() {
+			warnings = append(warnings, fmt.Sprintf("warning: %s [%s]", d.Message, d.Rule))
+		}
+		tc.SystemOut = strings.Join(warnings, "\n")
+
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+	}
+
+	doc := junitSuites{Tests: suite.Tests, Failures: suite.Failures, Suites: []junitSuite{suite}}
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/RyanTalbot/bartle/internal/lint"
+)
+
+// Entry is the lint result for one commit message.
+type Entry struct {
+	// Source is the message file the result came from, if any.
+	Source string
+	// SHA and Subject identify the commit when linting a range.
+	SHA     string
+	Subject string
+	Result  lint.Result
+}
+
+// Name is a human-readable label for the entry.
+func (e Entry) Name() string {
+	switch {
+	case e.SHA != "":
+		return strings.TrimSpace(e.SHA + " " + e.Subject)
+	case e.Source != "":
+		return e.Source
+	default:
+		return "commit message"
+	}
+}
+
+// messagePath is the location reported for messages that don't come from a
+// file, such as commits in a range or -m, by formats that require one.
+const messagePath = "COMMIT_EDITMSG"
+
+// path is the file the entry's message is reported against.
+func (e Entry) path() string {
+	if e.Source != "" {
+		return e.Source
+	}
+	return messagePath
+}
+
+// Writer renders entries in one machine-readable format.
+type Writer func(w io.Writer, entries []Entry) error
+
+var writers = map[string]Writer{
+	"json":       writeJSON,
+	"sarif":      writeSARIF,
+	"junit":      writeJUnit,
+	"checkstyle": writeCheckstyle,
+	"github":     writeGitHub,
+	"gitlab":     writeGitLab,
+}
+
+// CI providers DetectCI knows about.
+const (
+	CIGitHub = "github"
+	CIGitLab = "gitlab"
+)
+
+// DetectCI reports which CI provider we're running under, if any, using the
+// environment variables each provider sets on every job.
+func DetectCI(getenv func(string) string) string {
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		return CIGitHub
+	case getenv("GITLAB_CI") == "true":
+		return CIGitLab
+	default:
+		return ""
+	}
+}
+
+// Formats returns the supported format names, sorted.
+func Formats() []string {
+	names := make([]string, 0, len(writers))
+	for name := range writers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write renders entries in the named format.
+func Write(w io.Writer, format string, entries []Entry) error {
+	write, ok := writers[strings.ToLower(format)]
+	if !ok {
+		return fmt.Errorf("unknown format %q (allowed: text|%s)", format, strings.Join(Formats(), "|"))
+	}
+	return write(w, entries)
+}
+
+// Supported reports whether format names a machine-readable format.
+func Supported(format string) bool {
+	_, ok := writers[strings.ToLower(format)]
+	return ok
+}
+
+func allValid(entries []Entry) bool {
+	for _, e := range entries {
+		if !e.Result.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// spanOrStart fills in a span for diagnostics without one, since line and
+// column numbers are 1-based in every format we emit.
+func spanOrStart(s lint.Span) lint.Span {
+	if s.StartLine == 0 {
+		return lint.Span{StartLine: 1, StartCol: 1, EndLine: 1, EndCol: 1}
+	}
+	return s
+}
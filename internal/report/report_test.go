@@ -0,0 +1,129 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/RyanTalbot/bartle/internal/config"
+	"github.com/RyanTalbot/bartle/internal/lint"
+)
+
+func sampleEntries() []Entry {
+	cfg := config.Default()
+	return []Entry{
+		{SHA: "0123456789abcdef", Subject: "feat(ui): add dropdown", Result: lint.ValidateMessage("feat(ui): add dropdown", cfg)},
+		{SHA: "fedcba9876543210", Subject: "wip", Result: lint.ValidateMessage("wip", cfg)},
+	}
+}
+
+func TestWriteFormats(t *testing.T) {
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, format, sampleEntries()); err != nil {
+				t.Fatalf("Write(%s) error = %v", format, err)
+			}
+
+			var v any
+			switch format {
+			case "github":
+				if !bytes.HasPrefix(buf.Bytes(), []byte("::error ")) {
+					t.Fatalf("not a workflow command:\n%s", buf.String())
+				}
+			case "json", "sarif", "gitlab":
+				if err := json.Unmarshal(buf.Bytes(), &v); err != nil {
+					t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+				}
+			case "junit", "checkstyle":
+				if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+					t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+				}
+			default:
+				t.Fatalf("no assertion for format %s", format)
+			}
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "json", sampleEntries()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Valid || len(got.Results) != 2 {
+		t.Fatalf("want invalid report with 2 results, got %+v", got)
+	}
+	if d := got.Results[1].Diagnostics; len(d) != 1 || d[0].Rule != lint.RuleHeaderFormat || d[0].Line != 1 {
+		t.Fatalf("unexpected diagnostics for second commit: %+v", d)
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "yaml", nil); err == nil {
+		t.Fatal("want error for unknown format")
+	}
+}
+
+func TestWriteGitHub(t *testing.T) {
+	var buf bytes.Buffer
+	entries := []Entry{{Source: ".git/COMMIT_EDITMSG", Result: lint.ValidateMessage("wip", config.Default())}}
+	if err := Write(&buf, "github", entries); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "::error title=bartle%3A header-format,file=.git/COMMIT_EDITMSG,line=1,col=1::missing ':' separator (e.g., type(scope): subject)\n"
+	if buf.String() != want {
+		t.Fatalf("output mismatch\nwant: %q\ngot:  %q", want, buf.String())
+	}
+}
+
+func TestDetectCI(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{env: map[string]string{}, want: ""},
+		{env: map[string]string{"GITHUB_ACTIONS": "true"}, want: CIGitHub},
+		{env: map[string]string{"GITLAB_CI": "true"}, want: CIGitLab},
+	}
+	for _, tt := range tests {
+		if got := DetectCI(func(k string) string { return tt.env[k] }); got != tt.want {
+			t.Fatalf("DetectCI(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestWriteSARIFLocations(t *testing.T) {
+	entries := append(sampleEntries(), Entry{Result: lint.ValidateMessage("wip", config.Default())})
+	var buf bytes.Buffer
+	if err := Write(&buf, "sarif", entries); err != nil {
+		t.Fatal(err)
+	}
+
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	results := got.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("want 2 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if len(r.Locations) != 1 || r.Locations[0].PhysicalLocation == nil || r.Locations[0].PhysicalLocation.ArtifactLocation.URI == "" {
+			t.Fatalf("result %d has no physical location: %+v", i, r.Locations)
+		}
+	}
+	if fp := results[0].PartialFingerprints["commitSha"]; fp != "fedcba9876543210" {
+		t.Fatalf("commit result fingerprint = %q", fp)
+	}
+	if results[1].PartialFingerprints != nil {
+		t.Fatalf("-m result has fingerprints: %v", results[1].PartialFingerprints)
+	}
+}
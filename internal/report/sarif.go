@@ -0,0 +1,144 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/RyanTalbot/bartle/internal/lint"
+	"github.com/RyanTalbot/bartle/internal/version"
+)
+
+// Minimal SARIF 2.1.0 model: just what code-scanning uploads need.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysical `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogical `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysical struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           sarifRegion   `json:"region"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifLogical struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind"`
+}
+
+func writeSARIF(w io.Writer, entries []Entry) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "bartle",
+			InformationURI: "https://github.com/RyanTalbot/bartle",
+			Version:        version.Get().Version,
+		}},
+		Results: []sarifResult{},
+	}
+	for _, r := range lint.Rules() {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               r.ID,
+			ShortDescription: sarifMessage{Text: r.Description},
+		})
+	}
+
+	for _, e := range entries {
+		for _, d := range e.Result.Diagnostics {
+			res := sarifResult{
+				RuleID:  d.Rule,
+				Level:   sarifLevel(d.Severity),
+				Message: sarifMessage{Text: d.Message},
+			}
+			// Code scanning rejects results without a physical location, so
+			// commits and -m messages are reported against messagePath and
+			// told apart by their SHA.
+			loc := sarifLocation{PhysicalLocation: &sarifPhysical{
+				ArtifactLocation: sarifArtifact{URI: e.path()},
+				Region:           newSARIFRegion(d.Span),
+			}}
+			if e.SHA != "" {
+				loc.LogicalLocations = []sarifLogical{{
+					Name:               e.SHA,
+					FullyQualifiedName: e.Name(),
+					Kind:               "resource",
+				}}
+				res.PartialFingerprints = map[string]string{"commitSha": e.SHA}
+				res.Properties = map[string]string{"commitSha": e.SHA}
+			}
+			res.Locations = []sarifLocation{loc}
+			run.Results = append(run.Results, res)
+		}
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+func sarifLevel(s lint.Severity) string {
+	if s == lint.SeverityWarn {
+		return "warning"
+	}
+	return "error"
+}
+
+func newSARIFRegion(s lint.Span) sarifRegion {
+	s = spanOrStart(s)
+	return sarifRegion{StartLine: s.StartLine, StartColumn: s.StartCol, EndLine: s.EndLine, EndColumn: s.EndCol}
+}
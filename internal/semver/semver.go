@@ -0,0 +1,176 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version (https://semver.org). Build metadata is
+// dropped since it never affects precedence.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// Bump is the kind of version increment a change calls for.
+type Bump string
+
+const (
+	BumpNone  Bump = "none"
+	BumpPatch Bump = "patch"
+	BumpMinor Bump = "minor"
+	BumpMajor Bump = "major"
+)
+
+var bumpRank = map[Bump]int{BumpNone: 0, BumpPatch: 1, BumpMinor: 2, BumpMajor: 3}
+
+// ParseBump validates a bump name from configuration.
+func ParseBump(s string) (Bump, error) {
+	b := Bump(strings.ToLower(s))
+	if _, ok := bumpRank[b]; !ok {
+		return BumpNone, fmt.Errorf("invalid bump %q (allowed: major|minor|patch|none)", s)
+	}
+	return b, nil
+}
+
+// Max returns the larger of two bumps.
+func (b Bump) Max(other Bump) Bump {
+	if bumpRank[other] > bumpRank[b] {
+		return other
+	}
+	return b
+}
+
+// Parse parses "1.2.3", "v1.2.3" or "1.2.3-rc.1+build".
+func Parse(s string) (Version, error) {
+	raw := s
+	s = strings.TrimPrefix(s, "v")
+	s, _, _ = strings.Cut(s, "+")
+
+	var v Version
+	core, pre, hasPre := strings.Cut(s, "-")
+	if hasPre {
+		if pre == "" {
+			return Version{}, fmt.Errorf("invalid version %q: empty prerelease", raw)
+		}
+		v.Prerelease = pre
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: want MAJOR.MINOR.PATCH", raw)
+	}
+	nums := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || (len(p) > 1 && p[0] == '0') {
+			return Version{}, fmt.Errorf("invalid version %q: bad number %q", raw, p)
+		}
+		nums[i] = n
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1, ranking versions by SemVer precedence: a
+// prerelease comes before its release, and prereleases compare as in
+// comparePrerelease.
+func (v Version) Compare(o Version) int {
+	for _, d := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if d[0] != d[1] {
+			if d[0] < d[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	default:
+		return comparePrerelease(v.Prerelease, o.Prerelease)
+	}
+}
+
+// comparePrerelease compares dot-separated identifiers one by one (SemVer
+// §11): numeric ones numerically and below alphanumeric ones, others in
+// ASCII order. When one list runs out first, the shorter ranks lower, so
+// rc.2 < rc.10 and alpha < alpha.1.
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareIdentifier(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		if an == bn {
+			return 0
+		}
+		if an < bn {
+			return -1
+		}
+		return 1
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// Apply returns the version after a bump. Bumping a prerelease to its own
+// level releases it (1.2.0-rc.1 + minor = 1.2.0).
+func (v Version) Apply(b Bump) Version {
+	pre := v.Prerelease != ""
+	next := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	switch b {
+	case BumpMajor:
+		if !pre || v.Minor != 0 || v.Patch != 0 {
+			next.Major++
+		}
+		next.Minor, next.Patch = 0, 0
+	case BumpMinor:
+		if !pre || v.Patch != 0 {
+			next.Minor++
+		}
+		next.Patch = 0
+	case BumpPatch:
+		if !pre {
+			next.Patch++
+		}
+	default:
+		return v
+	}
+	return next
+}
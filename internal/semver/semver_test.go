@@ -0,0 +1,72 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1.2.3", want: "1.2.3"},
+		{in: "v0.10.0", want: "0.10.0"},
+		{in: "2.0.0-rc.1+build.5", want: "2.0.0-rc.1"},
+		{in: "1.2", wantErr: true},
+		{in: "01.2.3", wantErr: true},
+		{in: "latest", wantErr: true},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if err == nil && v.String() != tt.want {
+			t.Fatalf("Parse(%q) = %s, want %s", tt.in, v, tt.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		from string
+		bump Bump
+		want string
+	}{
+		{from: "1.2.3", bump: BumpNone, want: "1.2.3"},
+		{from: "1.2.3", bump: BumpPatch, want: "1.2.4"},
+		{from: "1.2.3", bump: BumpMinor, want: "1.3.0"},
+		{from: "1.2.3", bump: BumpMajor, want: "2.0.0"},
+		{from: "2.0.0-rc.1", bump: BumpMajor, want: "2.0.0"},
+		{from: "1.3.0-rc.1", bump: BumpPatch, want: "1.3.0"},
+		{from: "1.3.1-rc.1", bump: BumpMinor, want: "1.4.0"},
+	}
+	for _, tt := range tests {
+		v, _ := Parse(tt.from)
+		if got := v.Apply(tt.bump).String(); got != tt.want {
+			t.Fatalf("%s + %s = %s, want %s", tt.from, tt.bump, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a, _ := Parse("1.2.0-rc.1")
+	b, _ := Parse("1.2.0")
+	if a.Compare(b) != -1 || b.Compare(a) != 1 || b.Compare(b) != 0 {
+		t.Fatal("prerelease must sort before its release")
+	}
+}
+
+func TestComparePrerelease(t *testing.T) {
+	// Ascending, from the SemVer spec's example plus the rc.2/rc.10 case.
+	order := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0-rc.2", "1.0.0-rc.10", "1.0.0",
+	}
+	for i := 0; i+1 < len(order); i++ {
+		a, _ := Parse(order[i])
+		b, _ := Parse(order[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Fatalf("want %s < %s", order[i], order[i+1])
+		}
+	}
+}